@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/Fuwn/faustus/internal/config"
+	"github.com/Fuwn/faustus/internal/ui"
+	"os"
+	"strings"
+	"time"
+)
+
+type configFlags struct {
+	configPath      *string
+	claudeDir       *string
+	trashDir        *string
+	rootLabel       *string
+	theme           *string
+	stackWidth      *int
+	previewMessages *int
+	truncate        *int
+	messageTimeout  *time.Duration
+	dryRun          *bool
+	colors          map[string]string
+	roots           []config.Root
+	relocateRoots   []string
+	keys            map[string]config.KeyList
+}
+
+func registerConfigFlags(flagSet *flag.FlagSet) *configFlags {
+	defaultConfig := config.Default()
+	flags := &configFlags{
+		configPath: flagSet.String("config", config.Path(), "path to the configuration file"),
+		claudeDir: flagSet.String("claude-dir", defaultConfig.Paths.ClaudeDir,
+			"directory holding Claude Code's projects, following $CLAUDE_CONFIG_DIR when it is set"),
+		trashDir:  flagSet.String("trash-dir", "", "directory for trashed sessions (default <claude-dir>/faustus-trash)"),
+		rootLabel: flagSet.String("root-label", "", "label shown for sessions in --claude-dir when several roots are loaded"),
+		theme: flagSet.String("theme", defaultConfig.Interface.Theme,
+			"colour theme: auto, a built-in theme ("+strings.Join(ui.BuiltinThemeNames(), ", ")+"), or one from [themes]"),
+		stackWidth: flagSet.Int("stack-width", defaultConfig.Interface.StackWidth,
+			"terminal width below which the automatic layout stacks the preview under the list"),
+		previewMessages: flagSet.Int("preview-messages", defaultConfig.Preview.Messages,
+			"number of messages loaded into the preview"),
+		truncate: flagSet.Int("truncate", defaultConfig.Preview.Truncate,
+			"characters shown per preview message before truncating"),
+		messageTimeout: flagSet.Duration("message-timeout", defaultConfig.Interface.MessageTimeout.Duration,
+			"how long status messages stay visible"),
+		dryRun: flagSet.Bool("dry-run", false, "show a plan of every change for review before applying it"),
+		colors: map[string]string{},
+		keys:   map[string]config.KeyList{},
+	}
+
+	flagSet.Func("color", "override a palette colour as name=value, may be repeated", func(value string) error {
+		name, colorValue, found := strings.Cut(value, "=")
+
+		if !found {
+			return errors.New("expected name=value")
+		}
+
+		flags.colors[strings.TrimSpace(name)] = strings.TrimSpace(colorValue)
+
+		return nil
+	})
+
+	flagSet.Func("key", "rebind a key as name=key[,key…], may be repeated", func(value string) error {
+		name, keyNames, found := strings.Cut(value, "=")
+
+		if !found {
+			return errors.New("expected name=key[,key…]")
+		}
+
+		flags.keys[strings.TrimSpace(name)] = strings.Split(keyNames, ",")
+
+		return nil
+	})
+
+	flagSet.Func("root", "add another Claude data directory as [label=]dir, may be repeated", func(value string) error {
+		label, directory, found := strings.Cut(value, "=")
+
+		if !found {
+			label, directory = "", value
+		}
+
+		flags.roots = append(flags.roots, config.Root{Label: strings.TrimSpace(label), Dir: strings.TrimSpace(directory)})
+
+		return nil
+	})
+
+	flagSet.Func("relocate-root", "search this directory for moved project folders instead of $HOME, may be repeated",
+		func(value string) error {
+			flags.relocateRoots = append(flags.relocateRoots, strings.TrimSpace(value))
+
+			return nil
+		})
+
+	return flags
+}
+
+func (flags *configFlags) resolve(flagSet *flag.FlagSet) (config.Config, error) {
+	setFlags := map[string]bool{}
+
+	flagSet.Visit(func(setFlag *flag.Flag) {
+		setFlags[setFlag.Name] = true
+	})
+
+	loadedConfig, loadError := config.Load(*flags.configPath, setFlags["config"])
+
+	if setFlags["claude-dir"] {
+		loadedConfig.Paths.ClaudeDir = *flags.claudeDir
+	}
+
+	if setFlags["trash-dir"] {
+		loadedConfig.Paths.TrashDir = *flags.trashDir
+	}
+
+	if setFlags["root-label"] {
+		loadedConfig.Paths.Label = *flags.rootLabel
+	}
+
+	if setFlags["preview-messages"] {
+		loadedConfig.Preview.Messages = *flags.previewMessages
+	}
+
+	if setFlags["truncate"] {
+		loadedConfig.Preview.Truncate = *flags.truncate
+	}
+
+	if setFlags["message-timeout"] {
+		loadedConfig.Interface.MessageTimeout = config.Duration{Duration: *flags.messageTimeout}
+	}
+
+	if setFlags["theme"] {
+		loadedConfig.Interface.Theme = *flags.theme
+	}
+
+	if setFlags["stack-width"] {
+		loadedConfig.Interface.StackWidth = *flags.stackWidth
+	}
+
+	if setFlags["dry-run"] {
+		loadedConfig.Interface.DryRun = *flags.dryRun
+	}
+
+	loadedConfig.Roots = append(loadedConfig.Roots, flags.roots...)
+	loadedConfig.Paths.RelocateRoots = append(loadedConfig.Paths.RelocateRoots, flags.relocateRoots...)
+
+	for name, colorValue := range flags.colors {
+		loadedConfig.Colors[name] = colorValue
+	}
+
+	for name, keyNames := range flags.keys {
+		loadedConfig.Keys[name] = keyNames
+	}
+
+	return loadedConfig, errors.Join(loadError, loadedConfig.Validate())
+}
+
+func applyConfig(loadedConfig *config.Config) error {
+	claude.SetRoots(loadedConfig.ClaudeRoots())
+	claude.SetPreviewTruncation(loadedConfig.Preview.Truncate)
+	claude.SetRelocateRoots(loadedConfig.RelocateRoots())
+
+	palette, paletteError := loadedConfig.Palette()
+
+	if paletteError != nil {
+		return paletteError
+	}
+
+	return ui.SetColors(palette)
+}
+
+func reportConfigError(configError error) {
+	fmt.Fprintln(os.Stderr, "Invalid configuration:")
+
+	for _, line := range strings.Split(configError.Error(), "\n") {
+		fmt.Fprintf(os.Stderr, "  %s\n", line)
+	}
+}
+
+func runConfig(configPath string, loadedConfig *config.Config, arguments []string) int {
+	flagSet := flag.NewFlagSet("config", flag.ExitOnError)
+	showPath := flagSet.Bool("path", false, "print the configuration file path instead of its contents")
+
+	_ = flagSet.Parse(arguments)
+
+	if *showPath {
+		fmt.Println(configPath)
+
+		return 0
+	}
+
+	if encodeError := loadedConfig.Encode(os.Stdout); encodeError != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", encodeError)
+
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/Fuwn/faustus/internal/claude"
+	"os"
+)
+
+func runDoctor(arguments []string, defaultDryRun bool) int {
+	flagSet := flag.NewFlagSet("doctor", flag.ExitOnError)
+	fix := flagSet.Bool("fix", false, "repair the problems that can be fixed automatically")
+	dryRun := flagSet.Bool("dry-run", defaultDryRun, "with --fix, print the repair plan without applying it")
+
+	_ = flagSet.Parse(arguments)
+
+	report, diagnoseError := claude.Diagnose()
+
+	if diagnoseError != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning sessions: %v\n", diagnoseError)
+
+		return 1
+	}
+
+	fmt.Printf("Scanned %d project directories and %d session files\n", report.DirectoriesScanned, report.FilesScanned)
+
+	if len(report.Problems) == 0 {
+		fmt.Println("No problems found")
+
+		return 0
+	}
+
+	fmt.Println()
+
+	for _, problem := range report.Problems {
+		fmt.Printf("%-16s %s\n", problem.Kind, problem.Path)
+
+		if problem.SessionID != "" {
+			fmt.Printf("%-16s session %s\n", "", problem.SessionID)
+		}
+
+		fmt.Printf("%-16s %s\n\n", "", problem.Explanation)
+	}
+
+	fmt.Printf("%d problems found, %d fixable with --fix\n", len(report.Problems), report.FixableCount())
+
+	if !*fix {
+		return 1
+	}
+
+	plan := claude.PlanDoctorFix(report)
+
+	if *dryRun {
+		fmt.Println()
+
+		for _, line := range plan.Lines() {
+			fmt.Println(line)
+		}
+
+		return 1
+	}
+
+	appliedCount, applyError := plan.Apply()
+
+	fmt.Printf("Applied %d of %d repairs\n", appliedCount, len(plan.Operations))
+
+	if applyError != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", applyError)
+
+		return 1
+	}
+
+	if report.FixableCount() < len(report.Problems) {
+		return 1
+	}
+
+	return 0
+}
+
+func runReindex(arguments []string, defaultDryRun bool) int {
+	flagSet := flag.NewFlagSet("reindex", flag.ExitOnError)
+	dryRun := flagSet.Bool("dry-run", defaultDryRun, "print the rebuild plan without applying it")
+
+	_ = flagSet.Parse(arguments)
+
+	plan, planError := claude.PlanRebuildAllIndexes()
+
+	if planError != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning sessions: %v\n", planError)
+
+		return 1
+	}
+
+	if *dryRun {
+		for _, line := range plan.Lines() {
+			fmt.Println(line)
+		}
+
+		return 0
+	}
+
+	appliedCount, applyError := plan.Apply()
+
+	fmt.Printf("Rebuilt %d of %d indexes\n", appliedCount, len(plan.Operations))
+
+	if applyError != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", applyError)
+
+		return 1
+	}
+
+	return 0
+}
+
+func runTitle(arguments []string, defaultDryRun bool) int {
+	flagSet := flag.NewFlagSet("title", flag.ExitOnError)
+	all := flagSet.Bool("all", false, "also replace titles of sessions that already have one")
+	write := flagSet.Bool("write", false, "save the generated titles instead of only previewing them")
+	dryRun := flagSet.Bool("dry-run", defaultDryRun, "with --write, print the plan without applying it")
+
+	_ = flagSet.Parse(arguments)
+
+	sessions, loadError := claude.LoadAllSessions()
+
+	if loadError != nil {
+		fmt.Fprintf(os.Stderr, "Error loading sessions: %v\n", loadError)
+
+		return 1
+	}
+
+	plan := claude.PlanAutoTitle(sessions, *all)
+
+	if plan.IsEmpty() {
+		fmt.Println("No titles to generate")
+
+		return 0
+	}
+
+	for _, operation := range plan.Operations {
+		fmt.Println(operation.Description)
+	}
+
+	if !*write {
+		fmt.Printf("\n%d titles previewed; run with --write to save them\n", len(plan.Operations))
+
+		return 0
+	}
+
+	if *dryRun {
+		fmt.Println()
+
+		for _, line := range plan.Lines() {
+			fmt.Println(line)
+		}
+
+		return 0
+	}
+
+	appliedCount, applyError := plan.Apply()
+
+	fmt.Printf("\nTitled %d of %d sessions\n", appliedCount, len(plan.Operations))
+
+	if applyError != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", applyError)
+
+		return 1
+	}
+
+	return 0
+}
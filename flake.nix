@@ -25,7 +25,7 @@
 
           pname = "faustus";
           src = pkgs.lib.cleanSource ./.;
-          vendorHash = "sha256-RNbS40G+8rtwlSJgYLN1puTCytGfXdagQTEs6sIXwnM=";
+          vendorHash = "sha256-gE2S4VovVh59CRbQAlhJD6pSvue1tgfLo5yRoHucDt4=";
           ldflags = [
             "-s"
             "-w"
@@ -0,0 +1,535 @@
+package app
+
+import (
+	"fmt"
+	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"slices"
+	"strings"
+)
+
+var listActions = map[string]func(m *Model) tea.Cmd{
+	"quit": func(m *Model) tea.Cmd {
+		return tea.Quit
+	},
+	"help": func(m *Model) tea.Cmd {
+		m.showHelp = !m.showHelp
+
+		return nil
+	},
+	"preview": func(m *Model) tea.Cmd {
+		m.showPreview = !m.showPreview
+		m.previewFocus = false
+		m.previewScroll = 0
+		m.zoomed = false
+
+		m.invalidatePreviewCache()
+
+		return nil
+	},
+	"layout": func(m *Model) tea.Cmd {
+		m.layout = m.layout.Next()
+
+		m.saveState()
+
+		if m.layout == LayoutAuto {
+			m.setMessage(fmt.Sprintf("Layout: auto, stacked below %d columns", m.stackWidth))
+		} else {
+			m.setMessage("Layout: " + m.layout.String())
+		}
+
+		return nil
+	},
+	"zoom": func(m *Model) tea.Cmd {
+		if !m.showPreview {
+			m.setMessage(fmt.Sprintf("Open the preview with %s to zoom a pane", helpKey(m.keys.Preview)))
+
+			return nil
+		}
+
+		m.zoomed = !m.zoomed
+
+		return nil
+	},
+	"grow_list": func(m *Model) tea.Cmd {
+		m.adjustSplit(splitStep)
+
+		return nil
+	},
+	"shrink_list": func(m *Model) tea.Cmd {
+		m.adjustSplit(-splitStep)
+
+		return nil
+	},
+	"up": func(m *Model) tea.Cmd {
+		m.moveOrScroll(-1)
+
+		return nil
+	},
+	"down": func(m *Model) tea.Cmd {
+		m.moveOrScroll(1)
+
+		return nil
+	},
+	"half_up": func(m *Model) tea.Cmd {
+		m.moveOrScroll(-10)
+
+		return nil
+	},
+	"half_down": func(m *Model) tea.Cmd {
+		m.moveOrScroll(10)
+
+		return nil
+	},
+	"top": func(m *Model) tea.Cmd {
+		if m.showPreview && m.previewFocus {
+			m.previewScroll = 0
+		} else {
+			m.cursor = 0
+
+			m.ensureVisible()
+			m.invalidatePreviewCache()
+		}
+
+		return nil
+	},
+	"bottom": func(m *Model) tea.Cmd {
+		if m.showPreview && m.previewFocus {
+			m.previewScroll = 99999
+
+			m.clampPreviewScroll()
+		} else {
+			m.cursor = max(0, len(m.rows)-1)
+
+			m.ensureVisible()
+			m.invalidatePreviewCache()
+		}
+
+		return nil
+	},
+	"tab": func(m *Model) tea.Cmd {
+		if m.showPreview {
+			m.previewFocus = !m.previewFocus
+		} else if m.tab == TabSessions {
+			m.switchTab(TabTrash)
+		} else {
+			m.switchTab(TabSessions)
+		}
+
+		return nil
+	},
+	"left": func(m *Model) tea.Cmd {
+		m.switchTab(TabSessions)
+
+		return nil
+	},
+	"right": func(m *Model) tea.Cmd {
+		m.switchTab(TabTrash)
+
+		return nil
+	},
+	"sort": func(m *Model) tea.Cmd {
+		m.sortField = m.sortField.Next()
+
+		m.applySort()
+		m.saveState()
+
+		return nil
+	},
+	"sort_order": func(m *Model) tea.Cmd {
+		m.sortDescending = !m.sortDescending
+
+		m.applySort()
+		m.saveState()
+
+		return nil
+	},
+	"search": func(m *Model) tea.Cmd {
+		m.mode = ModeSearch
+
+		m.searchInput.Focus()
+
+		return textinput.Blink
+	},
+	"group": func(m *Model) tea.Cmd {
+		selectedID := ""
+
+		if session := m.cursorSession(); session != nil {
+			selectedID = session.SessionID
+		}
+
+		m.groupByProject = !m.groupByProject
+		m.cursor = 0
+		m.offset = 0
+
+		m.updateFiltered()
+		m.selectSession(selectedID)
+		m.invalidatePreviewCache()
+		m.saveState()
+
+		return nil
+	},
+	"project_name": func(m *Model) tea.Cmd {
+		m.projectNameStyle = m.projectNameStyle.Next()
+
+		claude.SetProjectNameStyle(m.projectNameStyle)
+		claude.RefreshProjectNames(m.sessions)
+		m.applySort()
+		m.saveState()
+		m.setMessage("Project names: " + m.projectNameStyle.String())
+
+		return nil
+	},
+	"reindex": func(m *Model) tea.Cmd {
+		var sessions []claude.Session
+
+		if group := m.cursorGroup(); group != nil {
+			sessions = m.groupSessions(group)
+		} else if session := m.cursorSession(); session != nil {
+			sessions = []claude.Session{*session}
+		} else {
+			return nil
+		}
+
+		plan := &claude.Plan{Description: "Rebuild project index", ContinueOnError: true}
+		rebuiltDirectories := map[string]bool{}
+
+		for sessionIndex := range sessions {
+			projectDirectory := claude.ProjectDir(&sessions[sessionIndex])
+
+			if !rebuiltDirectories[projectDirectory] {
+				rebuiltDirectories[projectDirectory] = true
+
+				plan.Merge(claude.PlanRebuildIndex(projectDirectory))
+			}
+		}
+
+		m.runPlan(plan, func(appliedCount int) string { return fmt.Sprintf("Rebuilt %d indexes", appliedCount) })
+
+		return nil
+	},
+	"auto_title": func(m *Model) tea.Cmd {
+		var plan *claude.Plan
+
+		if group := m.cursorGroup(); group != nil {
+			plan = claude.PlanAutoTitle(m.groupSessions(group), false)
+		} else if session := m.cursorSession(); session != nil {
+			plan = claude.PlanAutoTitle([]claude.Session{*session}, true)
+		} else {
+			return nil
+		}
+
+		if plan.IsEmpty() {
+			m.setMessage("No new titles to suggest")
+
+			return nil
+		}
+
+		m.previewPlan(plan, func(appliedCount int) string { return fmt.Sprintf("Titled %d sessions", appliedCount) })
+
+		return nil
+	},
+	"doctor": func(m *Model) tea.Cmd {
+		m.setMessage("Checking session storage …")
+
+		return runDoctor()
+	},
+	"dry_run": func(m *Model) tea.Cmd {
+		m.dryRun = !m.dryRun
+
+		if m.dryRun {
+			m.setMessage("Dry run on: changes are shown as a plan before applying")
+		} else {
+			m.setMessage("Dry run off")
+		}
+
+		return nil
+	},
+	"relocate": func(m *Model) tea.Cmd {
+		missingCount := 0
+
+		for _, session := range m.sessions {
+			if session.IsProjectMissing() {
+				missingCount += 1
+			}
+		}
+
+		if missingCount == 0 {
+			m.setMessage("No sessions with missing project folders")
+
+			return nil
+		}
+
+		m.setMessage(fmt.Sprintf("Searching for new locations of %d sessions …", missingCount))
+
+		return findRelocations(slices.Clone(m.sessions))
+	},
+	"collapse": func(m *Model) tea.Cmd {
+		if group := m.cursorGroup(); group != nil {
+			m.toggleGroup(group)
+		}
+
+		return nil
+	},
+	"export": func(m *Model) tea.Cmd {
+		m.exportCursor(claude.ExportMarkdown)
+
+		return nil
+	},
+	"export_html": func(m *Model) tea.Cmd {
+		m.exportCursor(claude.ExportHTML)
+
+		return nil
+	},
+	"delete": func(m *Model) tea.Cmd {
+		if group := m.cursorGroup(); group != nil {
+			if m.tab == TabTrash {
+				m.confirmAction = ConfirmPermanentDeleteProject
+			} else {
+				m.confirmAction = ConfirmDeleteProject
+			}
+
+			m.confirmGroup = group
+			m.mode = ModeConfirm
+		} else if m.cursorSession() != nil {
+			if m.tab == TabTrash {
+				m.confirmAction = ConfirmPermanentDelete
+			} else {
+				m.confirmAction = ConfirmDelete
+			}
+
+			m.mode = ModeConfirm
+		}
+
+		return nil
+	},
+	"restore": func(m *Model) tea.Cmd {
+		if m.tab != TabTrash {
+			return nil
+		}
+
+		if group := m.cursorGroup(); group != nil {
+			m.confirmAction = ConfirmRestoreProject
+			m.confirmGroup = group
+			m.mode = ModeConfirm
+		} else if m.cursorSession() != nil {
+			m.confirmAction = ConfirmRestore
+			m.mode = ModeConfirm
+		}
+
+		return nil
+	},
+	"rename": func(m *Model) tea.Cmd {
+		session := m.cursorSession()
+
+		if session == nil {
+			return nil
+		}
+
+		m.renameInput.SetValue(session.Summary)
+		m.renameInput.Focus()
+
+		m.mode = ModeRename
+
+		return textinput.Blink
+	},
+	"tag": func(m *Model) tea.Cmd {
+		session := m.cursorSession()
+
+		if session == nil {
+			return nil
+		}
+
+		m.tagInput.SetValue(strings.Join(session.Tags, " "))
+		m.tagInput.CursorEnd()
+		m.tagInput.Focus()
+
+		m.mode = ModeTag
+
+		return textinput.Blink
+	},
+	"pin": func(m *Model) tea.Cmd {
+		session := m.cursorSession()
+
+		if session == nil {
+			return nil
+		}
+
+		message := "Pinned"
+
+		if session.Pinned {
+			message = "Unpinned"
+		}
+
+		m.runPlan(claude.PlanSetPinned(session, !session.Pinned), func(int) string { return message })
+
+		return nil
+	},
+	"note": func(m *Model) tea.Cmd {
+		session := m.cursorSession()
+
+		if session == nil {
+			return nil
+		}
+
+		m.noteInput.SetValue(session.Note)
+		m.noteInput.SetWidth(min(80, max(20, m.width-8)))
+
+		m.mode = ModeNote
+
+		return m.noteInput.Focus()
+	},
+	"move_root": func(m *Model) tea.Cmd {
+		m.startRootTransfer(false)
+
+		return nil
+	},
+	"copy_root": func(m *Model) tea.Cmd {
+		m.startRootTransfer(true)
+
+		return nil
+	},
+	"fork": func(m *Model) tea.Cmd {
+		session := m.cursorSession()
+
+		if session == nil {
+			return nil
+		}
+
+		if session.InTrash {
+			m.setMessage("Restore the session before forking it")
+
+			return nil
+		}
+
+		plan, forkedID := claude.PlanForkSession(session)
+
+		m.runPlan(plan, func(int) string { return "Forked as " + forkedID })
+		m.selectSession(forkedID)
+
+		return nil
+	},
+	"palette": func(m *Model) tea.Cmd {
+		return m.openPalette()
+	},
+	"tag_sidebar": func(m *Model) tea.Cmd {
+		m.showTagSidebar = !m.showTagSidebar
+
+		m.saveState()
+
+		return nil
+	},
+	"reassign": func(m *Model) tea.Cmd {
+		return m.startReassign(false)
+	},
+	"reassign_all": func(m *Model) tea.Cmd {
+		return m.startReassign(true)
+	},
+	"clear": func(m *Model) tea.Cmd {
+		if m.tab == TabTrash {
+			m.confirmAction = ConfirmEmptyTrash
+			m.mode = ModeConfirm
+		}
+
+		return nil
+	},
+	"deep_search": func(m *Model) tea.Cmd {
+		m.mode = ModeDeepSearch
+
+		m.deepSearchInput.SetValue(m.deepSearchQuery)
+		m.deepSearchInput.Focus()
+
+		return textinput.Blink
+	},
+	"next_match": func(m *Model) tea.Cmd {
+		m.stepMatch(1)
+
+		return nil
+	},
+	"prev_match": func(m *Model) tea.Cmd {
+		m.stepMatch(-1)
+
+		return nil
+	},
+}
+
+func (m *Model) moveOrScroll(lines int) {
+	if m.showPreview && m.previewFocus {
+		m.previewScroll += lines
+
+		m.clampPreviewScroll()
+
+		return
+	}
+
+	cursor := max(0, min(len(m.rows)-1, m.cursor+lines))
+
+	if cursor == m.cursor {
+		return
+	}
+
+	m.cursor = cursor
+
+	m.ensureVisible()
+	m.invalidatePreviewCache()
+}
+
+func (m *Model) exportCursor(exportFormat claude.ExportFormat) {
+	if group := m.cursorGroup(); group != nil {
+		m.runPlan(claude.PlanExportSessions(m.groupSessions(group), exportFormat), func(appliedCount int) string {
+			return fmt.Sprintf("Exported %d sessions as %s to %s", appliedCount, exportFormat, claude.ExportDir())
+		})
+	} else if session := m.cursorSession(); session != nil {
+		exportPath := claude.ExportPath(session, exportFormat)
+
+		m.runPlan(claude.PlanExportSessions([]claude.Session{*session}, exportFormat), func(int) string {
+			return "Exported to " + exportPath
+		})
+	}
+}
+
+func (m *Model) startReassign(reassignAll bool) tea.Cmd {
+	if group := m.cursorGroup(); group != nil && group.pinned {
+		m.setMessage("Pinned sessions span several folders; reassign them one at a time")
+
+		return nil
+	} else if group != nil {
+		m.reassignFrom = group.path
+		reassignAll = true
+	} else if session := m.cursorSession(); session != nil {
+		m.reassignFrom = session.ProjectPath
+	} else {
+		return nil
+	}
+
+	if reassignAll && m.reassignFrom == "" {
+		m.setMessage("Sessions without a project path cannot be reassigned together; reassign them one at a time")
+
+		return nil
+	}
+
+	m.reassignInput.SetValue(m.reassignFrom)
+	m.reassignInput.CursorEnd()
+	m.reassignInput.Focus()
+
+	m.reassignAll = reassignAll
+	m.mode = ModeReassign
+	m.reassignCompletions = nil
+	m.reassignConfirmMissing = false
+	m.reassignTarget = m.inspectReassignTarget()
+
+	return textinput.Blink
+}
+
+func (m *Model) stepMatch(direction int) {
+	if m.showPreview && m.previewFocus && len(m.previewSearchMatches) > 0 {
+		m.previewSearchIndex = (m.previewSearchIndex + direction + len(m.previewSearchMatches)) % len(m.previewSearchMatches)
+
+		m.scrollToPreviewMatch()
+	} else if len(m.deepSearchResults) > 0 {
+		m.deepSearchIndex = (m.deepSearchIndex + direction + len(m.deepSearchResults)) % len(m.deepSearchResults)
+
+		m.jumpToSearchResult()
+	}
+}
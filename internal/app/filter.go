@@ -0,0 +1,75 @@
+package app
+
+import (
+	"github.com/Fuwn/faustus/internal/claude"
+	"slices"
+	"strings"
+)
+
+type filterQualifier struct {
+	name  string
+	value string
+}
+
+var qualifierNames = []string{"branch", "remote", "path", "tag", "root"}
+
+func parseFilterQuery(query string) (string, []filterQualifier) {
+	var terms []string
+	var qualifiers []filterQualifier
+
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		name, value, hasQualifier := strings.Cut(field, ":")
+
+		if hasQualifier && value != "" && isQualifierName(name) {
+			qualifiers = append(qualifiers, filterQualifier{name: name, value: value})
+
+			continue
+		}
+
+		terms = append(terms, field)
+	}
+
+	return strings.Join(terms, " "), qualifiers
+}
+
+func isQualifierName(name string) bool {
+	for _, qualifierName := range qualifierNames {
+		if qualifierName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesQualifier(session *claude.Session, qualifier filterQualifier) bool {
+	switch qualifier.name {
+	case "branch":
+		gitInfo := claude.CachedSessionGitInfo(session)
+
+		switch qualifier.value {
+		case "gone", "merged", "live":
+			return gitInfo.BranchState.String() == qualifier.value
+		}
+
+		return strings.Contains(strings.ToLower(session.GitBranch), qualifier.value)
+	case "remote":
+		return strings.Contains(strings.ToLower(claude.CachedSessionGitInfo(session).RemoteURL), qualifier.value)
+	case "path":
+		if qualifier.value == "missing" {
+			return session.IsProjectMissing()
+		}
+
+		return strings.Contains(strings.ToLower(session.ResolvedPath), qualifier.value)
+	case "tag":
+		if qualifier.value == "none" {
+			return len(session.Tags) == 0
+		}
+
+		return slices.Contains(session.Tags, qualifier.value)
+	case "root":
+		return strings.ToLower(session.Root) == qualifier.value
+	}
+
+	return true
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"github.com/Fuwn/faustus/internal/claude"
+	"time"
+)
+
+type listRow struct {
+	group        *projectGroup
+	sessionIndex int
+}
+
+type projectGroup struct {
+	key          string
+	name         string
+	path         string
+	sessionCount int
+	lastActivity time.Time
+	collapsed    bool
+	pinned       bool
+}
+
+const pinnedGroupKey = "\x00pinned"
+
+func groupKey(session *claude.Session) string {
+	if session.ResolvedPath != "" {
+		return session.ResolvedPath
+	}
+
+	return session.ProjectName
+}
+
+func (m *Model) inPinnedSection(session *claude.Session) bool {
+	return m.tab == TabSessions && session.Pinned
+}
+
+func (m *Model) sessionGroupKey(session *claude.Session) string {
+	if m.inPinnedSection(session) {
+		return pinnedGroupKey
+	}
+
+	return groupKey(session)
+}
+
+func (m *Model) buildRows() {
+	m.rows = nil
+
+	var pinnedIndexes []int
+
+	pinnedGroup := &projectGroup{
+		key:       pinnedGroupKey,
+		name:      "★ Pinned",
+		collapsed: m.collapsedGroups[pinnedGroupKey],
+		pinned:    true,
+	}
+
+	for index := range m.filtered {
+		session := &m.filtered[index]
+
+		if !m.inPinnedSection(session) {
+			continue
+		}
+
+		pinnedGroup.sessionCount += 1
+
+		if session.Modified.After(pinnedGroup.lastActivity) {
+			pinnedGroup.lastActivity = session.Modified
+		}
+
+		pinnedIndexes = append(pinnedIndexes, index)
+	}
+
+	if len(pinnedIndexes) > 0 {
+		m.rows = append(m.rows, listRow{group: pinnedGroup, sessionIndex: -1})
+
+		if !pinnedGroup.collapsed {
+			for _, sessionIndex := range pinnedIndexes {
+				m.rows = append(m.rows, listRow{group: pinnedGroup, sessionIndex: sessionIndex})
+			}
+		}
+	}
+
+	if !m.groupByProject {
+		for index := range m.filtered {
+			if !m.inPinnedSection(&m.filtered[index]) {
+				m.rows = append(m.rows, listRow{sessionIndex: index})
+			}
+		}
+
+		return
+	}
+
+	var groupOrder []*projectGroup
+
+	groups := map[string]*projectGroup{}
+	members := map[string][]int{}
+
+	for index := range m.filtered {
+		session := &m.filtered[index]
+
+		if m.inPinnedSection(session) {
+			continue
+		}
+
+		key := groupKey(session)
+		group, exists := groups[key]
+
+		if !exists {
+			group = &projectGroup{
+				key:       key,
+				name:      session.ProjectName,
+				path:      session.ResolvedPath,
+				collapsed: m.collapsedGroups[key],
+			}
+			groups[key] = group
+			groupOrder = append(groupOrder, group)
+		}
+
+		group.sessionCount += 1
+
+		if session.Modified.After(group.lastActivity) {
+			group.lastActivity = session.Modified
+		}
+
+		members[key] = append(members[key], index)
+	}
+
+	for _, group := range groupOrder {
+		m.rows = append(m.rows, listRow{group: group, sessionIndex: -1})
+
+		if group.collapsed {
+			continue
+		}
+
+		for _, sessionIndex := range members[group.key] {
+			m.rows = append(m.rows, listRow{group: group, sessionIndex: sessionIndex})
+		}
+	}
+}
+
+func (row listRow) isHeader() bool {
+	return row.sessionIndex == -1
+}
+
+func (m *Model) cursorRow() *listRow {
+	if m.cursor >= 0 && m.cursor < len(m.rows) {
+		return &m.rows[m.cursor]
+	}
+
+	return nil
+}
+
+func (m *Model) cursorSession() *claude.Session {
+	row := m.cursorRow()
+
+	if row == nil || row.isHeader() {
+		return nil
+	}
+
+	return &m.filtered[row.sessionIndex]
+}
+
+func (m *Model) cursorGroup() *projectGroup {
+	row := m.cursorRow()
+
+	if row == nil || !row.isHeader() {
+		return nil
+	}
+
+	return row.group
+}
+
+func (m *Model) groupSessions(group *projectGroup) []claude.Session {
+	var sessions []claude.Session
+
+	for _, session := range m.filtered {
+		if m.sessionGroupKey(&session) == group.key {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions
+}
+
+func (m *Model) toggleGroup(group *projectGroup) {
+	m.collapsedGroups[group.key] = !group.collapsed
+
+	m.buildRows()
+
+	for rowIndex, row := range m.rows {
+		if row.isHeader() && row.group.key == group.key {
+			m.cursor = rowIndex
+
+			break
+		}
+	}
+
+	m.ensureVisible()
+}
+
+func (m *Model) rowForSession(sessionID string) int {
+	for rowIndex, row := range m.rows {
+		if !row.isHeader() && m.filtered[row.sessionIndex].SessionID == sessionID {
+			return rowIndex
+		}
+	}
+
+	for _, session := range m.filtered {
+		if session.SessionID == sessionID && m.collapsedGroups[m.sessionGroupKey(&session)] {
+			m.collapsedGroups[m.sessionGroupKey(&session)] = false
+
+			m.buildRows()
+
+			return m.rowForSession(sessionID)
+		}
+	}
+
+	return -1
+}
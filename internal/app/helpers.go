@@ -2,6 +2,8 @@ package app
 
 import (
 	"fmt"
+	"github.com/Fuwn/faustus/internal/ui"
+	"github.com/charmbracelet/lipgloss"
 	"strings"
 	"time"
 )
@@ -42,6 +44,30 @@ func formatTime(timestamp time.Time) string {
 	}
 }
 
+func formatCount(count int) string {
+	switch {
+	case count >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(count)/1_000_000)
+	case count >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(count)/1_000)
+	default:
+		return fmt.Sprintf("%d", count)
+	}
+}
+
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
 func max(first, second int) int {
 	if first > second {
 		return first
@@ -58,14 +84,18 @@ func min(first, second int) int {
 	return second
 }
 
-func highlightMatches(text, query string) string {
+func highlightMatches(text, query string, baseStyle lipgloss.Style) string {
 	if query == "" {
-		return text
+		return baseStyle.Render(text)
 	}
 
 	queryLower := strings.ToLower(query)
 	textLower := strings.ToLower(text)
 
+	if len(textLower) != len(text) {
+		return baseStyle.Render(text)
+	}
+
 	var result strings.Builder
 
 	lastEnd := 0
@@ -74,20 +104,22 @@ func highlightMatches(text, query string) string {
 		index := strings.Index(textLower[lastEnd:], queryLower)
 
 		if index == -1 {
-			result.WriteString(text[lastEnd:])
+			if lastEnd < len(text) {
+				result.WriteString(baseStyle.Render(text[lastEnd:]))
+			}
 
 			break
 		}
 
 		matchStart := lastEnd + index
 
-		result.WriteString(text[lastEnd:matchStart])
+		if matchStart > lastEnd {
+			result.WriteString(baseStyle.Render(text[lastEnd:matchStart]))
+		}
 
-		matchEnd := matchStart + len(query)
+		matchEnd := matchStart + len(queryLower)
 
-		result.WriteString("\033[43;30m")
-		result.WriteString(text[matchStart:matchEnd])
-		result.WriteString("\033[0m")
+		result.WriteString(ui.MatchStyle.Render(text[matchStart:matchEnd]))
 
 		lastEnd = matchEnd
 	}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"github.com/Fuwn/faustus/internal/ui"
+	"github.com/charmbracelet/lipgloss"
+	"math"
+)
+
+type Layout int
+
+const (
+	LayoutAuto Layout = iota
+	LayoutSideBySide
+	LayoutStacked
+)
+
+const (
+	minimumPaneHeight = 3
+	splitStep         = 0.05
+	defaultSplitRatio = 0.5
+)
+
+var layoutNames = []string{"auto", "side-by-side", "stacked"}
+
+func (layout Layout) String() string {
+	if layout < 0 || int(layout) >= len(layoutNames) {
+		return layoutNames[LayoutAuto]
+	}
+
+	return layoutNames[layout]
+}
+
+func (layout Layout) Next() Layout {
+	return Layout((int(layout) + 1) % len(layoutNames))
+}
+
+func ParseLayout(name string) (Layout, bool) {
+	for layoutIndex, layoutName := range layoutNames {
+		if layoutName == name {
+			return Layout(layoutIndex), true
+		}
+	}
+
+	return LayoutAuto, false
+}
+
+func clampSplitRatio(ratio float64) float64 {
+	if ratio == 0 || math.IsNaN(ratio) {
+		return defaultSplitRatio
+	}
+
+	return math.Max(minimumRatio, math.Min(maximumRatio, ratio))
+}
+
+func (m Model) stacked() bool {
+	switch m.layout {
+	case LayoutStacked:
+		return true
+	case LayoutSideBySide:
+		return false
+	}
+
+	return m.width < m.stackWidth
+}
+
+func (m *Model) adjustSplit(delta float64) {
+	if !m.showPreview || m.zoomed {
+		return
+	}
+
+	m.splitRatio = clampSplitRatio(m.splitRatio + delta)
+
+	m.saveState()
+}
+
+func (m Model) renderSplitView() string {
+	listWidth := m.listWidth()
+	previewWidth := m.previewWidth()
+	listHeight := m.listHeight()
+	previewHeight := m.previewHeight()
+
+	var listStyle, previewStyle lipgloss.Style
+
+	if m.previewFocus {
+		listStyle = ui.ListBoxStyle
+		previewStyle = ui.PreviewFocusedStyle
+	} else {
+		listStyle = ui.ListBoxFocusedStyle
+		previewStyle = ui.PreviewStyle
+	}
+
+	if m.zoomed && m.previewFocus {
+		return previewStyle.
+			Width(previewWidth).
+			Height(previewHeight).
+			Render(m.renderPreview(previewWidth-2, previewHeight-2))
+	}
+
+	listBox := listStyle.
+		Width(listWidth).
+		Height(listHeight).
+		Render(m.renderListCompact(listWidth-2, listHeight-2))
+
+	if m.zoomed {
+		return listBox
+	}
+
+	previewBox := previewStyle.
+		Width(previewWidth).
+		Height(previewHeight).
+		Render(m.renderPreview(previewWidth-2, previewHeight-2))
+
+	if m.stacked() {
+		return lipgloss.JoinVertical(lipgloss.Left, listBox, previewBox)
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, listBox, " ", previewBox)
+}
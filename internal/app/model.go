@@ -2,9 +2,13 @@ package app
 
 import (
 	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/Fuwn/faustus/internal/config"
+	"github.com/Fuwn/faustus/internal/state"
 	"github.com/Fuwn/faustus/internal/ui"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"slices"
 	"time"
 )
 
@@ -24,6 +28,13 @@ const (
 	ModeRename
 	ModeConfirm
 	ModeReassign
+	ModeRelocate
+	ModePlan
+	ModeDoctor
+	ModeTag
+	ModeNote
+	ModeRoot
+	ModePalette
 )
 
 type ConfirmAction int
@@ -34,41 +45,85 @@ const (
 	ConfirmRestore
 	ConfirmEmptyTrash
 	ConfirmPermanentDelete
+	ConfirmDeleteProject
+	ConfirmRestoreProject
+	ConfirmPermanentDeleteProject
 )
 
 type Model struct {
-	sessions             []claude.Session
-	filtered             []claude.Session
-	cursor               int
-	offset               int
-	width                int
-	height               int
-	tab                  Tab
-	mode                 Mode
-	confirmAction        ConfirmAction
-	searchInput          textinput.Model
-	renameInput          textinput.Model
-	keys                 ui.KeyMap
-	showHelp             bool
-	message              string
-	messageTime          time.Time
-	showPreview          bool
-	previewFocus         bool
-	previewScroll        int
-	previewCache         *claude.PreviewContent
-	previewFor           string
-	deepSearchInput      textinput.Model
-	deepSearchResults    []claude.SearchResult
-	deepSearchIndex      int
-	deepSearchQuery      string
-	previewSearchQuery   string
-	previewSearchMatches []int
-	previewSearchIndex   int
-	reassignInput        textinput.Model
-	reassignAll          bool
+	sessions                []claude.Session
+	filtered                []claude.Session
+	cursor                  int
+	offset                  int
+	width                   int
+	height                  int
+	tab                     Tab
+	mode                    Mode
+	confirmAction           ConfirmAction
+	searchInput             textinput.Model
+	renameInput             textinput.Model
+	keys                    ui.KeyMap
+	showHelp                bool
+	message                 string
+	messageTime             time.Time
+	showPreview             bool
+	previewFocus            bool
+	previewScroll           int
+	previewCache            *claude.PreviewContent
+	previewFor              string
+	previewCommits          []claude.GitCommit
+	previewCommitsFor       string
+	previewCommitsRequested string
+	gitCacheStale           bool
+	tokenUsagePending       bool
+	tokenUsageLoading       bool
+	deepSearchInput         textinput.Model
+	deepSearchResults       []claude.SearchResult
+	deepSearchIndex         int
+	deepSearchQuery         string
+	previewSearchQuery      string
+	previewSearchMatches    []int
+	previewSearchIndex      int
+	reassignInput           textinput.Model
+	reassignAll             bool
+	sortField               claude.SortField
+	sortDescending          bool
+	rows                    []listRow
+	groupByProject          bool
+	collapsedGroups         map[string]bool
+	confirmGroup            *projectGroup
+	reassignFrom            string
+	projectNameStyle        claude.ProjectNameStyle
+	relocations             []claude.RelocationProposal
+	relocationSelected      []bool
+	relocationCursor        int
+	reassignTarget          reassignTarget
+	reassignCompletions     []string
+	reassignConfirmMissing  bool
+	dryRun                  bool
+	pendingPlan             *claude.Plan
+	pendingPlanDescribe     func(appliedCount int) string
+	planScroll              int
+	doctorReport            *claude.DoctorReport
+	doctorScroll            int
+	tagInput                textinput.Model
+	showTagSidebar          bool
+	noteInput               textarea.Model
+	rootTargets             []claude.Root
+	rootCursor              int
+	rootCopy                bool
+	paletteInput            textinput.Model
+	paletteCursor           int
+	splitRatio              float64
+	draggingDivider         bool
+	layout                  Layout
+	zoomed                  bool
+	stackWidth              int
+	previewMessages         int
+	messageTimeout          time.Duration
 }
 
-func NewModel(sessions []claude.Session) Model {
+func NewModel(sessions []claude.Session, loadedConfig *config.Config) Model {
 	searchInput := textinput.New()
 	searchInput.Placeholder = "Filter sessions"
 	searchInput.CharLimit = 100
@@ -85,21 +140,128 @@ func NewModel(sessions []claude.Session) Model {
 	reassignInput.Placeholder = "Enter new project path"
 	reassignInput.CharLimit = 500
 	reassignInput.Width = 80
+	tagInput := textinput.New()
+	tagInput.Placeholder = "Tags separated by spaces"
+	tagInput.CharLimit = 200
+	tagInput.Width = 60
+	paletteInput := textinput.New()
+	paletteInput.Placeholder = "Type a command"
+	paletteInput.Prompt = ": "
+	paletteInput.CharLimit = 100
+	paletteInput.Width = 50
+	noteInput := textarea.New()
+	noteInput.Placeholder = "Why does this session matter?"
+	noteInput.ShowLineNumbers = false
+	noteInput.CharLimit = 2000
+	noteInput.SetWidth(80)
+	noteInput.SetHeight(5)
+	savedState := state.Load()
+	sortField, _ := claude.ParseSortField(savedState.SortField)
+	projectNameStyle, _ := claude.ParseProjectNameStyle(savedState.ProjectNames)
+	layout, _ := ParseLayout(savedState.Layout)
+	keyMap, keyError := loadedConfig.KeyMap()
+
+	if keyError != nil {
+		keyMap = ui.DefaultKeyMap()
+	}
+
+	claude.SetProjectNameStyle(projectNameStyle)
+	claude.RefreshProjectNames(sessions)
+
 	model := Model{
-		sessions:        sessions,
-		keys:            ui.DefaultKeyMap(),
-		searchInput:     searchInput,
-		renameInput:     renameInput,
-		deepSearchInput: deepSearchInput,
-		reassignInput:   reassignInput,
-		showPreview:     false,
+		sessions:         sessions,
+		keys:             keyMap,
+		searchInput:      searchInput,
+		renameInput:      renameInput,
+		deepSearchInput:  deepSearchInput,
+		reassignInput:    reassignInput,
+		tagInput:         tagInput,
+		noteInput:        noteInput,
+		paletteInput:     paletteInput,
+		splitRatio:       clampSplitRatio(savedState.SplitRatio),
+		layout:           layout,
+		stackWidth:       loadedConfig.Interface.StackWidth,
+		showPreview:      false,
+		sortField:        sortField,
+		sortDescending:   savedState.SortDescending,
+		groupByProject:   savedState.GroupByProject,
+		collapsedGroups:  map[string]bool{},
+		projectNameStyle: projectNameStyle,
+		showTagSidebar:   savedState.ShowTags,
+		dryRun:           loadedConfig.Interface.DryRun,
+		previewMessages:  loadedConfig.Preview.Messages,
+		messageTimeout:   loadedConfig.Interface.MessageTimeout.Duration,
 	}
 
-	model.updateFiltered()
+	model.applySort()
 
 	return model
 }
 
+type gitCacheWarmedMsg struct{}
+
+func warmGitCache(sessions []claude.Session) tea.Cmd {
+	return func() tea.Msg {
+		claude.WarmGitCache(sessions)
+
+		return gitCacheWarmedMsg{}
+	}
+}
+
+func (m *Model) requestGitCache() tea.Cmd {
+	if !m.gitCacheStale {
+		return nil
+	}
+
+	m.gitCacheStale = false
+
+	return warmGitCache(slices.Clone(m.sessions))
+}
+
+type tokenUsageLoadedMsg struct{}
+
+func loadTokenUsage(sessions []claude.Session) tea.Cmd {
+	return func() tea.Msg {
+		claude.LoadTokenUsage(sessions)
+
+		return tokenUsageLoadedMsg{}
+	}
+}
+
+func (m *Model) requestTokenUsage() tea.Cmd {
+	if !m.tokenUsagePending || m.tokenUsageLoading {
+		return nil
+	}
+
+	m.tokenUsagePending = false
+	m.tokenUsageLoading = true
+
+	return loadTokenUsage(slices.Clone(m.sessions))
+}
+
+type relocationsFoundMsg struct {
+	proposals []claude.RelocationProposal
+}
+
+func findRelocations(sessions []claude.Session) tea.Cmd {
+	return func() tea.Msg {
+		return relocationsFoundMsg{proposals: claude.ProposeRelocations(sessions)}
+	}
+}
+
+type doctorReportMsg struct {
+	report *claude.DoctorReport
+	err    error
+}
+
+func runDoctor() tea.Cmd {
+	return func() tea.Msg {
+		report, diagnoseError := claude.Diagnose()
+
+		return doctorReportMsg{report: report, err: diagnoseError}
+	}
+}
+
 func (m Model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, warmGitCache(slices.Clone(m.sessions)))
 }
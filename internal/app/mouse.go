@@ -0,0 +1,207 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+const (
+	tabGap           = "    "
+	wheelLines       = 3
+	minimumPaneWidth = 20
+	minimumRatio     = 0.2
+	maximumRatio     = 0.8
+)
+
+type mouseArea int
+
+const (
+	areaNone mouseArea = iota
+	areaSessionsTab
+	areaBinTab
+	areaList
+	areaDivider
+	areaPreview
+)
+
+func (m Model) contentTop() int {
+	return strings.Count(m.renderTop(), "\n")
+}
+
+func (m Model) contentLeft() int {
+	if m.showTagSidebar {
+		return lipgloss.Width(m.renderTagSidebar()) + 1
+	}
+
+	return 0
+}
+
+func (m Model) mouseAreaAt(x, y int) mouseArea {
+	if y == 1 {
+		sessionsTab, binTab := m.renderTabLabels()
+		sessionsWidth := lipgloss.Width(sessionsTab)
+		binStart := sessionsWidth + len(tabGap)
+
+		switch {
+		case x < sessionsWidth:
+			return areaSessionsTab
+		case x >= binStart && x < binStart+lipgloss.Width(binTab):
+			return areaBinTab
+		}
+
+		return areaNone
+	}
+
+	top := m.contentTop()
+	left := m.contentLeft()
+
+	if y < top || x < left || y >= top+m.contentHeight()+2 {
+		return areaNone
+	}
+
+	if !m.showPreview {
+		return areaList
+	}
+
+	if m.zoomed {
+		if m.previewFocus {
+			return areaPreview
+		}
+
+		return areaList
+	}
+
+	if m.stacked() {
+		dividerRow := top + m.listHeight() + 1
+
+		switch {
+		case y >= dividerRow && y <= dividerRow+1:
+			return areaDivider
+		case y < dividerRow:
+			return areaList
+		}
+
+		return areaPreview
+	}
+
+	dividerColumn := left + m.listWidth() + 2
+
+	switch {
+	case x >= dividerColumn-1 && x <= dividerColumn+1:
+		return areaDivider
+	case x < dividerColumn:
+		return areaList
+	}
+
+	return areaPreview
+}
+
+func (m Model) rowAt(y int) (int, bool) {
+	line := y - m.contentTop()
+	rowIndex := m.offset + line/2
+
+	if m.showPreview {
+		rowIndex = m.offset + line - 1
+
+		if line < 1 || line > m.visibleItemCount() {
+			return 0, false
+		}
+	}
+
+	if rowIndex < m.offset || rowIndex >= min(m.offset+m.visibleItemCount(), len(m.rows)) {
+		return 0, false
+	}
+
+	return rowIndex, true
+}
+
+func (m Model) handleMouse(mouseMessage tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.mode != ModeNormal {
+		return m, nil
+	}
+
+	if m.draggingDivider {
+		switch mouseMessage.Action {
+		case tea.MouseActionMotion:
+			m.resizeSplit(mouseMessage.X, mouseMessage.Y)
+		case tea.MouseActionRelease:
+			m.draggingDivider = false
+
+			m.saveState()
+		}
+
+		return m, nil
+	}
+
+	area := m.mouseAreaAt(mouseMessage.X, mouseMessage.Y)
+
+	switch mouseMessage.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		lines := wheelLines
+
+		if mouseMessage.Button == tea.MouseButtonWheelUp {
+			lines = -wheelLines
+		}
+
+		if area == areaPreview {
+			m.previewScroll += lines
+
+			m.clampPreviewScroll()
+		} else if area == areaList {
+			m.scrollList(lines / wheelLines)
+		}
+	case tea.MouseButtonLeft:
+		if mouseMessage.Action != tea.MouseActionPress {
+			break
+		}
+
+		switch area {
+		case areaSessionsTab:
+			m.switchTab(TabSessions)
+		case areaBinTab:
+			m.switchTab(TabTrash)
+		case areaDivider:
+			m.draggingDivider = true
+		case areaPreview:
+			m.previewFocus = true
+		case areaList:
+			m.previewFocus = false
+
+			if rowIndex, found := m.rowAt(mouseMessage.Y); found && rowIndex != m.cursor {
+				m.cursor = rowIndex
+
+				m.ensureVisible()
+				m.invalidatePreviewCache()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) scrollList(rows int) {
+	visible := m.visibleItemCount()
+	m.offset = max(0, min(len(m.rows)-visible, m.offset+rows))
+	cursor := max(m.offset, min(m.offset+visible-1, m.cursor))
+
+	if cursor != m.cursor && cursor < len(m.rows) {
+		m.cursor = cursor
+
+		m.invalidatePreviewCache()
+	}
+}
+
+func (m *Model) resizeSplit(x, y int) {
+	if m.stacked() {
+		if available := m.contentHeight() - 2; available > 0 {
+			m.splitRatio = clampSplitRatio(float64(y-m.contentTop()-1) / float64(available))
+		}
+
+		return
+	}
+
+	if width := m.contentWidth(); width > 0 {
+		m.splitRatio = clampSplitRatio(float64(x-m.contentLeft()-2) / float64(width))
+	}
+}
@@ -0,0 +1,260 @@
+package app
+
+import (
+	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/Fuwn/faustus/internal/ui"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const paletteVisibleCommands = 10
+
+type paletteCommand struct {
+	title   string
+	name    string
+	binding key.Binding
+	run     func(m *Model) tea.Cmd
+}
+
+var paletteHiddenBindings = []string{"up", "down", "half_up", "half_down", "palette"}
+
+func (m *Model) openPalette() tea.Cmd {
+	m.paletteInput.SetValue("")
+	m.paletteInput.Focus()
+
+	m.paletteCursor = 0
+	m.mode = ModePalette
+
+	return textinput.Blink
+}
+
+func (m *Model) closePalette() {
+	m.paletteInput.Blur()
+
+	m.mode = ModeNormal
+}
+
+func (m *Model) paletteCommands() []paletteCommand {
+	var commands []paletteCommand
+
+	for _, name := range ui.ListBindingNames() {
+		if slices.Contains(paletteHiddenBindings, name) || !m.commandAvailable(name) {
+			continue
+		}
+
+		binding, _ := m.keys.Binding(name)
+		title := capitalise(binding.Help().Desc)
+
+		if name == "delete" && m.tab == TabTrash {
+			title = "Delete permanently"
+		}
+
+		commands = append(commands, paletteCommand{
+			title:   title,
+			name:    name,
+			binding: binding,
+			run:     listActions[name],
+		})
+	}
+
+	for sortField := m.sortField.Next(); sortField != m.sortField; sortField = sortField.Next() {
+		commands = append(commands, paletteCommand{
+			title: "Sort by " + sortField.String(),
+			name:  "sort",
+			run: func(m *Model) tea.Cmd {
+				m.sortField = sortField
+
+				m.applySort()
+				m.saveState()
+
+				return nil
+			},
+		})
+	}
+
+	if claude.HasMultipleRoots() {
+		for _, root := range claude.Roots() {
+			commands = append(commands, paletteCommand{
+				title: "Show only root " + root.Label,
+				name:  "root",
+				run: func(m *Model) tea.Cmd {
+					m.setFilter("root:" + root.Label)
+
+					return nil
+				},
+			})
+		}
+	}
+
+	if m.searchInput.Value() != "" {
+		commands = append(commands, paletteCommand{
+			title: "Clear filter",
+			name:  "filter",
+			run: func(m *Model) tea.Cmd {
+				m.setFilter("")
+
+				return nil
+			},
+		})
+	}
+
+	return commands
+}
+
+func (m *Model) commandAvailable(name string) bool {
+	hasSession := m.cursorSession() != nil
+	hasRow := hasSession || m.cursorGroup() != nil
+
+	switch name {
+	case "restore":
+		return m.tab == TabTrash && hasRow
+	case "clear":
+		return m.tab == TabTrash
+	case "left":
+		return m.tab != TabSessions
+	case "right":
+		return m.tab != TabTrash
+	case "rename", "tag", "pin", "note":
+		return hasSession
+	case "fork":
+		return hasSession && m.tab == TabSessions
+	case "move_root", "copy_root":
+		return hasSession && claude.HasMultipleRoots()
+	case "collapse":
+		return m.cursorGroup() != nil
+	case "zoom", "grow_list", "shrink_list":
+		return m.showPreview
+	case "next_match", "prev_match":
+		return len(m.deepSearchResults) > 0 || len(m.previewSearchMatches) > 0
+	case "delete", "export", "export_html", "reassign", "reassign_all", "reindex", "auto_title":
+		return hasRow
+	}
+
+	return true
+}
+
+func (m *Model) setFilter(query string) {
+	m.searchInput.SetValue(query)
+
+	m.cursor = 0
+	m.offset = 0
+
+	m.updateFiltered()
+	m.invalidatePreviewCache()
+}
+
+func (m *Model) paletteMatches() []paletteCommand {
+	commands := m.paletteCommands()
+	query := strings.ReplaceAll(m.paletteInput.Value(), " ", "")
+
+	if query == "" {
+		return commands
+	}
+
+	type scoredCommand struct {
+		command paletteCommand
+		score   int
+	}
+
+	var scored []scoredCommand
+
+	for _, command := range commands {
+		titleScore, titleMatches := fuzzyScore(query, command.title)
+		nameScore, nameMatches := fuzzyScore(query, strings.ReplaceAll(command.name, "_", " "))
+
+		if !titleMatches && !nameMatches {
+			continue
+		}
+
+		scored = append(scored, scoredCommand{command: command, score: max(titleScore, nameScore)})
+	}
+
+	sort.SliceStable(scored, func(first, second int) bool {
+		return scored[first].score > scored[second].score
+	})
+
+	matches := make([]paletteCommand, 0, len(scored))
+
+	for _, scoredMatch := range scored {
+		matches = append(matches, scoredMatch.command)
+	}
+
+	return matches
+}
+
+func fuzzyScore(query, text string) (int, bool) {
+	queryRunes := []rune(strings.ToLower(query))
+	textRunes := []rune(strings.ToLower(text))
+	score := 0
+	queryIndex := 0
+	previousMatch := -2
+
+	for textIndex, character := range textRunes {
+		if queryIndex == len(queryRunes) {
+			break
+		}
+
+		if character != queryRunes[queryIndex] {
+			continue
+		}
+
+		score += 1
+
+		if textIndex == previousMatch+1 {
+			score += 3
+		}
+
+		if textIndex == 0 || textRunes[textIndex-1] == ' ' {
+			score += 5
+		}
+
+		previousMatch = textIndex
+		queryIndex += 1
+	}
+
+	if queryIndex < len(queryRunes) {
+		return 0, false
+	}
+
+	return score, true
+}
+
+func (m Model) handlePaletteMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape):
+		m.closePalette()
+
+		return m, nil
+	case key.Matches(keyMessage, m.keys.Enter):
+		matches := m.paletteMatches()
+
+		m.closePalette()
+
+		if len(matches) == 0 {
+			return m, nil
+		}
+
+		command := matches[min(m.paletteCursor, len(matches)-1)].run(&m)
+
+		return m, command
+	case key.Matches(keyMessage, m.keys.PaletteUp):
+		m.paletteCursor = max(0, m.paletteCursor-1)
+
+		return m, nil
+	case key.Matches(keyMessage, m.keys.PaletteDown):
+		m.paletteCursor = min(max(0, len(m.paletteMatches())-1), m.paletteCursor+1)
+
+		return m, nil
+	}
+
+	var command tea.Cmd
+
+	m.paletteInput, command = m.paletteInput.Update(keyMessage)
+	m.paletteCursor = 0
+
+	return m, command
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"github.com/Fuwn/faustus/internal/claude"
+)
+
+const planVisibleLines = 14
+
+func (m *Model) Notify(message string) {
+	m.setMessage(message)
+}
+
+func (m *Model) runPlan(plan *claude.Plan, describe func(appliedCount int) string) {
+	if plan.IsEmpty() {
+		m.setMessage("Nothing to change")
+
+		return
+	}
+
+	if m.dryRun {
+		m.previewPlan(plan, describe)
+
+		return
+	}
+
+	m.applyPlan(plan, describe)
+}
+
+func (m *Model) previewPlan(plan *claude.Plan, describe func(appliedCount int) string) {
+	if plan.IsEmpty() {
+		m.setMessage("Nothing to change")
+
+		return
+	}
+
+	m.pendingPlan = plan
+	m.pendingPlanDescribe = describe
+	m.planScroll = 0
+	m.mode = ModePlan
+}
+
+func (m *Model) applyPlan(plan *claude.Plan, describe func(appliedCount int) string) {
+	appliedCount, applyError := plan.Apply()
+
+	switch {
+	case applyError != nil && len(plan.Operations) > 1:
+		m.setMessage(fmt.Sprintf("Error after %d of %d operations: %v", appliedCount, len(plan.Operations), applyError))
+	case applyError != nil:
+		m.setMessage(fmt.Sprintf("Error: %v", applyError))
+	default:
+		m.setMessage(describe(appliedCount))
+	}
+
+	m.reloadSessions()
+}
+
+func (m *Model) planLines() []string {
+	if m.pendingPlan == nil {
+		return nil
+	}
+
+	return m.pendingPlan.Lines()
+}
@@ -2,27 +2,60 @@ package app
 
 import (
 	"github.com/Fuwn/faustus/internal/claude"
+	tea "github.com/charmbracelet/bubbletea"
 	"strings"
 )
 
+type previewCommitsLoadedMsg struct {
+	sessionID string
+	commits   []claude.GitCommit
+}
+
 func (m *Model) invalidatePreviewCache() {
 	m.previewCache = nil
 	m.previewFor = ""
 	m.previewScroll = 0
+	m.previewCommitsRequested = ""
 }
 
-func (m *Model) preview() *claude.PreviewContent {
-	if len(m.filtered) == 0 {
+func (m *Model) requestPreviewCommits() tea.Cmd {
+	session := m.cursorSession()
+
+	if !m.showPreview || session == nil || m.previewCommitsRequested == session.SessionID {
 		return nil
 	}
 
-	session := &m.filtered[m.cursor]
+	m.previewCommitsRequested = session.SessionID
+	requestedSession := *session
+
+	return func() tea.Msg {
+		return previewCommitsLoadedMsg{
+			sessionID: requestedSession.SessionID,
+			commits:   claude.SessionCommits(&requestedSession),
+		}
+	}
+}
+
+func (m Model) cachedPreviewCommits(session *claude.Session) []claude.GitCommit {
+	if m.previewCommitsFor != session.SessionID {
+		return nil
+	}
+
+	return m.previewCommits
+}
+
+func (m *Model) preview() *claude.PreviewContent {
+	session := m.cursorSession()
+
+	if session == nil {
+		return nil
+	}
 
 	if m.previewCache != nil && m.previewFor == session.SessionID {
 		return m.previewCache
 	}
 
-	previewContent := claude.LoadSessionPreview(session, 50)
+	previewContent := claude.LoadSessionPreview(session, m.previewMessages)
 	m.previewCache = &previewContent
 	m.previewFor = session.SessionID
 
@@ -51,11 +84,8 @@ func (m *Model) calculatePreviewMetrics() previewMetrics {
 
 	lineCount := 0
 
-	if m.cursor < len(m.filtered) {
-		lineCount += 1
-		lineCount += 1
-		lineCount += 1
-		lineCount += 1
+	if session := m.cursorSession(); session != nil {
+		lineCount += len(m.previewHeaderLines(session, preview, m.previewWidth()-2))
 	}
 
 	for _, previewMessage := range preview.Messages {
@@ -73,7 +103,7 @@ func (m *Model) calculatePreviewMetrics() previewMetrics {
 }
 
 func (m *Model) clampPreviewScroll() {
-	height := m.listHeight() - 2
+	height := m.previewHeight() - 2
 	metrics := m.calculatePreviewMetrics()
 	maxScroll := max(0, metrics.totalLines-height+1)
 
@@ -99,7 +129,7 @@ func (m *Model) scrollToPreviewMatch() {
 	}
 
 	lineNumber := metrics.messageLines[matchMessageIndex]
-	height := m.listHeight() - 2
+	height := m.previewHeight() - 2
 	m.previewScroll = max(0, lineNumber-height/3)
 
 	m.clampPreviewScroll()
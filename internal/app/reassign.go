@@ -0,0 +1,133 @@
+package app
+
+import (
+	"github.com/Fuwn/faustus/internal/claude"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type reassignTarget struct {
+	path             string
+	exists           bool
+	isDirectory      bool
+	gitInfo          claude.GitInfo
+	affectedCount    int
+	existingSessions int
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDirectory, homeError := os.UserHomeDir(); homeError == nil {
+			return homeDirectory + path[1:]
+		}
+	}
+
+	return path
+}
+
+func collapseHome(path, original string) string {
+	if !strings.HasPrefix(original, "~") {
+		return path
+	}
+
+	homeDirectory, homeError := os.UserHomeDir()
+
+	if homeError != nil {
+		return path
+	}
+
+	if trimmed, isUnderHome := strings.CutPrefix(path, homeDirectory); isUnderHome {
+		return "~" + trimmed
+	}
+
+	return path
+}
+
+func completePath(input string) (string, []string) {
+	expanded := expandHome(input)
+	directory, prefix := filepath.Split(expanded)
+
+	if directory == "" {
+		directory = "." + string(filepath.Separator)
+	}
+
+	entries, readError := os.ReadDir(directory)
+
+	if readError != nil {
+		return input, nil
+	}
+
+	var matches []string
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if entry.IsDir() || isDirectoryLink(filepath.Join(directory, name)) {
+			matches = append(matches, name)
+		}
+	}
+
+	if len(matches) == 0 {
+		return input, nil
+	}
+
+	if len(matches) == 1 {
+		return collapseHome(directory+matches[0]+string(filepath.Separator), input), nil
+	}
+
+	common := matches[0]
+
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	return collapseHome(directory+common, input), matches
+}
+
+func isDirectoryLink(path string) bool {
+	fileInfo, statError := os.Stat(path)
+
+	return statError == nil && fileInfo.IsDir()
+}
+
+func (m *Model) inspectReassignTarget() reassignTarget {
+	target := reassignTarget{path: filepath.Clean(expandHome(m.reassignInput.Value()))}
+
+	if fileInfo, statError := os.Stat(target.path); statError == nil {
+		target.exists = true
+		target.isDirectory = fileInfo.IsDir()
+		target.gitInfo = claude.LoadGitInfo(target.path, "")
+	}
+
+	inTrash := false
+	root := claude.PrimaryRoot()
+
+	if m.reassignAll {
+		for _, session := range m.sessions {
+			if session.ProjectPath == m.reassignFrom {
+				target.affectedCount += 1
+			}
+		}
+	} else if session := m.cursorSession(); session != nil {
+		target.affectedCount = 1
+		inTrash = session.InTrash
+		root = claude.RootForSession(session)
+	}
+
+	if target.path != filepath.Clean(m.reassignFrom) {
+		target.existingSessions = claude.CountSessionFiles(claude.ProjectDirForPath(root, target.path, inTrash))
+	}
+
+	return target
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"github.com/Fuwn/faustus/internal/claude"
+)
+
+func rootTargets(session *claude.Session) []claude.Root {
+	var targets []claude.Root
+
+	for _, root := range claude.Roots() {
+		if root.Label != session.Root {
+			targets = append(targets, root)
+		}
+	}
+
+	return targets
+}
+
+func (m *Model) startRootTransfer(copySession bool) {
+	session := m.cursorSession()
+
+	if session == nil {
+		return
+	}
+
+	targets := rootTargets(session)
+
+	if len(targets) == 0 {
+		m.setMessage("Only one Claude data root is loaded; add more under [[roots]] or with --root")
+
+		return
+	}
+
+	if len(targets) == 1 {
+		m.transferToRoot(session, targets[0], copySession)
+
+		return
+	}
+
+	m.rootTargets = targets
+	m.rootCursor = 0
+	m.rootCopy = copySession
+	m.mode = ModeRoot
+}
+
+func (m *Model) transferToRoot(session *claude.Session, destination claude.Root, copySession bool) {
+	planTransfer, verb := claude.PlanMoveToRoot, "Moved"
+
+	if copySession {
+		planTransfer, verb = claude.PlanCopyToRoot, "Copied"
+	}
+
+	plan, planError := planTransfer(session, destination)
+
+	if planError != nil {
+		m.setMessage(fmt.Sprintf("Error: %v", planError))
+
+		return
+	}
+
+	m.runPlan(plan, func(int) string { return verb + " to " + destination.Label })
+}
@@ -2,7 +2,8 @@ package app
 
 import (
 	"github.com/Fuwn/faustus/internal/claude"
-	"strings"
+	"slices"
+	"sort"
 )
 
 func (m *Model) jumpToSearchResult() {
@@ -17,41 +18,38 @@ func (m *Model) jumpToSearchResult() {
 		m.showPreview = true
 		m.previewFocus = true
 		m.previewSearchQuery = m.deepSearchQuery
+		session := m.cursorSession()
+		previewContent := claude.LoadSessionPreviewAround(session, m.previewMessages, result.ID)
+		m.previewCache = &previewContent
+		m.previewFor = session.SessionID
+		m.previewSearchMatches = claude.SearchPreview(m.previewCache, m.deepSearchQuery)
+		m.previewSearchIndex = 0
+		targetIndex := previewContent.MessageIndex(result.ID)
+
+		if targetIndex != -1 {
+			insertAt := sort.SearchInts(m.previewSearchMatches, targetIndex)
+
+			if insertAt == len(m.previewSearchMatches) || m.previewSearchMatches[insertAt] != targetIndex {
+				m.previewSearchMatches = slices.Insert(m.previewSearchMatches, insertAt, targetIndex)
+			}
 
-		if preview := m.preview(); preview != nil {
-			m.previewSearchMatches = claude.SearchPreview(preview, m.deepSearchQuery)
-			m.previewSearchIndex = 0
-
-			if len(m.previewSearchMatches) > 0 && result.Content != "" {
-				bestMatch := 0
-
-				for matchIndex, messageIndex := range m.previewSearchMatches {
-					if messageIndex < len(preview.Messages) {
-						if strings.Contains(strings.ToLower(preview.Messages[messageIndex].Content),
-							strings.ToLower(extractSearchSnippet(result.Content))) {
-							bestMatch = matchIndex
-
-							break
-						}
-					}
-				}
+			m.previewSearchIndex = insertAt
+		}
 
-				m.previewSearchIndex = bestMatch
-			}
+		m.scrollToPreviewMatch()
 
-			m.scrollToPreviewMatch()
+		if result.Role == "note" {
+			m.previewScroll = 0
 		}
 	}
 
-	for index, session := range m.filtered {
-		if session.SessionID == result.Session.SessionID {
-			m.cursor = index
+	if rowIndex := m.rowForSession(result.Session.SessionID); rowIndex != -1 {
+		m.cursor = rowIndex
 
-			m.ensureVisible()
-			setupPreview()
+		m.ensureVisible()
+		setupPreview()
 
-			return
-		}
+		return
 	}
 
 	for _, session := range m.sessions {
@@ -66,30 +64,14 @@ func (m *Model) jumpToSearchResult() {
 				m.updateFiltered()
 			}
 
-			for filteredIndex, filteredSession := range m.filtered {
-				if filteredSession.SessionID == session.SessionID {
-					m.cursor = filteredIndex
-
-					m.ensureVisible()
-					setupPreview()
+			if rowIndex := m.rowForSession(session.SessionID); rowIndex != -1 {
+				m.cursor = rowIndex
 
-					break
-				}
+				m.ensureVisible()
+				setupPreview()
 			}
 
 			break
 		}
 	}
 }
-
-func extractSearchSnippet(content string) string {
-	content = strings.TrimPrefix(content, "… ")
-	content = strings.TrimSuffix(content, " …")
-	content = strings.TrimSpace(content)
-
-	if len(content) > 30 {
-		content = content[:30]
-	}
-
-	return content
-}
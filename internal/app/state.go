@@ -2,12 +2,13 @@ package app
 
 import (
 	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/Fuwn/faustus/internal/state"
 	"strings"
 	"time"
 )
 
 func (m *Model) updateFiltered() {
-	query := strings.ToLower(m.searchInput.Value())
+	query, qualifiers := parseFilterQuery(m.searchInput.Value())
 	m.filtered = nil
 
 	for _, session := range m.sessions {
@@ -20,29 +21,46 @@ func (m *Model) updateFiltered() {
 		}
 
 		if query != "" {
-			searchable := strings.ToLower(session.Summary + " " + session.FirstPrompt + " " + session.ProjectName + " " + session.GitBranch)
+			searchable := strings.ToLower(session.Summary + " " + session.FirstPrompt + " " + session.ProjectName + " " +
+				session.GitBranch + " " + strings.Join(session.Tags, " ") + " " + session.Note)
 
 			if !strings.Contains(searchable, query) {
 				continue
 			}
 		}
 
+		if !m.matchesQualifiers(&session, qualifiers) {
+			continue
+		}
+
 		m.filtered = append(m.filtered, session)
 	}
 
-	if m.cursor >= len(m.filtered) {
-		m.cursor = max(0, len(m.filtered)-1)
+	m.buildRows()
+
+	if m.cursor >= len(m.rows) {
+		m.cursor = max(0, len(m.rows)-1)
 	}
 }
 
+func (m *Model) matchesQualifiers(session *claude.Session, qualifiers []filterQualifier) bool {
+	for _, qualifier := range qualifiers {
+		if !matchesQualifier(session, qualifier) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (m *Model) setMessage(statusMessage string) {
 	m.message = statusMessage
 	m.messageTime = time.Now()
 }
 
 func (m *Model) selectedSession() *claude.Session {
-	if m.cursor >= 0 && m.cursor < len(m.filtered) {
-		sessionID := m.filtered[m.cursor].SessionID
+	if cursorSession := m.cursorSession(); cursorSession != nil {
+		sessionID := cursorSession.SessionID
 
 		for index := range m.sessions {
 			if m.sessions[index].SessionID == sessionID {
@@ -55,12 +73,12 @@ func (m *Model) selectedSession() *claude.Session {
 }
 
 func (m *Model) updateFilteredFromOriginal() {
-	if m.cursor >= 0 && m.cursor < len(m.filtered) {
-		sessionID := m.filtered[m.cursor].SessionID
+	if cursorSession := m.cursorSession(); cursorSession != nil {
+		sessionID := cursorSession.SessionID
 
 		for _, session := range m.sessions {
 			if session.SessionID == sessionID {
-				m.filtered[m.cursor] = session
+				*cursorSession = session
 
 				break
 			}
@@ -79,12 +97,70 @@ func (m *Model) reloadSessions() {
 
 	m.sessions = sessions
 
+	claude.ResetPathCache()
+	claude.ResetGitCache()
+
+	m.gitCacheStale = true
+
+	m.applySort()
+
+	if m.cursor >= len(m.rows) {
+		m.cursor = max(0, len(m.rows)-1)
+	}
+}
+
+func (m *Model) applySort() {
+	selectedID := ""
+
+	if session := m.selectedSession(); session != nil {
+		selectedID = session.SessionID
+	}
+
+	if m.sortField == claude.SortTokens && !claude.ApplyCachedTokenUsage(m.sessions) {
+		m.tokenUsagePending = true
+	}
+
+	claude.SortSessions(m.sessions, m.sortField, m.sortDescending)
 	m.updateFiltered()
+	m.selectSession(selectedID)
 	m.invalidatePreviewCache()
+}
+
+func (m *Model) selectSession(sessionID string) {
+	if rowIndex := m.rowForSession(sessionID); rowIndex != -1 {
+		m.cursor = rowIndex
+
+		m.ensureVisible()
+	}
+}
+
+func (m *Model) saveState() {
+	savedState := state.State{
+		SortField:      m.sortField.String(),
+		SortDescending: m.sortDescending,
+		GroupByProject: m.groupByProject,
+		ProjectNames:   m.projectNameStyle.String(),
+		ShowTags:       m.showTagSidebar,
+		Layout:         m.layout.String(),
+		SplitRatio:     m.splitRatio,
+	}
+
+	if saveError := state.Save(savedState); saveError != nil {
+		m.setMessage("Could not save state: " + saveError.Error())
+	}
+}
 
-	if m.cursor >= len(m.filtered) {
-		m.cursor = max(0, len(m.filtered)-1)
+func (m *Model) switchTab(tab Tab) {
+	if m.tab == tab {
+		return
 	}
+
+	m.tab = tab
+	m.cursor = 0
+	m.offset = 0
+
+	m.updateFiltered()
+	m.invalidatePreviewCache()
 }
 
 func (m *Model) ensureVisible() {
@@ -99,19 +175,37 @@ func (m *Model) ensureVisible() {
 	}
 }
 
-func (m Model) listWidth() int {
-	if m.showPreview {
-		return m.width / 2
+func (m Model) contentWidth() int {
+	if m.showTagSidebar {
+		return max(1, m.width-tagSidebarWidth-3)
 	}
 
 	return m.width
 }
 
+func (m Model) listWidth() int {
+	contentWidth := m.contentWidth()
+
+	if !m.showPreview {
+		return contentWidth
+	}
+
+	if m.stacked() || m.zoomed {
+		return max(1, contentWidth-2)
+	}
+
+	return max(minimumPaneWidth, min(contentWidth-minimumPaneWidth-5, int(float64(contentWidth)*m.splitRatio)))
+}
+
 func (m Model) previewWidth() int {
-	return m.width - m.listWidth() - 3
+	if m.stacked() || m.zoomed {
+		return max(1, m.contentWidth()-2)
+	}
+
+	return m.contentWidth() - m.listWidth() - 5
 }
 
-func (m Model) listHeight() int {
+func (m Model) contentHeight() int {
 	reserved := 8
 
 	if m.showHelp {
@@ -121,6 +215,24 @@ func (m Model) listHeight() int {
 	return max(1, m.height-reserved)
 }
 
+func (m Model) listHeight() int {
+	if !m.showPreview || !m.stacked() || m.zoomed {
+		return m.contentHeight()
+	}
+
+	available := m.contentHeight() - 2
+
+	return max(1, min(available-minimumPaneHeight, max(minimumPaneHeight, int(float64(available)*m.splitRatio))))
+}
+
+func (m Model) previewHeight() int {
+	if !m.stacked() || m.zoomed {
+		return m.contentHeight()
+	}
+
+	return max(1, m.contentHeight()-2-m.listHeight())
+}
+
 func (m Model) visibleItemCount() int {
 	if m.showPreview {
 		return m.listHeight() - 2
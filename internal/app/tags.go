@@ -0,0 +1,34 @@
+package app
+
+import (
+	"github.com/Fuwn/faustus/internal/claude"
+	"slices"
+)
+
+const tagSidebarWidth = 24
+
+func (m *Model) tabSessions() []claude.Session {
+	var sessions []claude.Session
+
+	for _, session := range m.sessions {
+		if session.InTrash == (m.tab == TabTrash) {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions
+}
+
+func (m *Model) activeTagFilters() []string {
+	var tags []string
+
+	_, qualifiers := parseFilterQuery(m.searchInput.Value())
+
+	for _, qualifier := range qualifiers {
+		if qualifier.name == "tag" && !slices.Contains(tags, qualifier.value) {
+			tags = append(tags, qualifier.value)
+		}
+	}
+
+	return tags
+}
@@ -3,21 +3,82 @@ package app
 import (
 	"fmt"
 	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/Fuwn/faustus/internal/ui"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"strings"
 	"time"
 )
 
 func (m Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
+	updatedModel, command := m.update(message)
+	model := updatedModel.(Model)
+	commitsCommand := model.requestPreviewCommits()
+	usageCommand := model.requestTokenUsage()
+	gitCommand := model.requestGitCache()
+
+	return model, tea.Batch(command, commitsCommand, usageCommand, gitCommand)
+}
+
+func (m Model) update(message tea.Msg) (tea.Model, tea.Cmd) {
 	switch typedMessage := message.(type) {
 	case tea.WindowSizeMsg:
 		m.width = typedMessage.Width
 		m.height = typedMessage.Height
 
 		return m, nil
+	case gitCacheWarmedMsg:
+		m.updateFiltered()
+
+		return m, nil
+	case tokenUsageLoadedMsg:
+		m.tokenUsageLoading = false
+
+		if m.sortField == claude.SortTokens {
+			m.applySort()
+		}
+
+		return m, nil
+	case previewCommitsLoadedMsg:
+		if typedMessage.sessionID == m.previewCommitsRequested {
+			m.previewCommits = typedMessage.commits
+			m.previewCommitsFor = typedMessage.sessionID
+		}
+
+		return m, nil
+	case relocationsFoundMsg:
+		if len(typedMessage.proposals) == 0 {
+			m.setMessage("No relocation candidates found")
+
+			return m, nil
+		}
+
+		m.relocations = typedMessage.proposals
+		m.relocationSelected = make([]bool, len(typedMessage.proposals))
+		m.relocationCursor = 0
+		m.mode = ModeRelocate
+
+		for proposalIndex := range m.relocationSelected {
+			m.relocationSelected[proposalIndex] = true
+		}
+
+		return m, nil
+	case doctorReportMsg:
+		if typedMessage.err != nil {
+			m.setMessage(fmt.Sprintf("Error: %v", typedMessage.err))
+
+			return m, nil
+		}
+
+		m.doctorReport = typedMessage.report
+		m.doctorScroll = 0
+		m.mode = ModeDoctor
+
+		return m, nil
+	case tea.MouseMsg:
+		return m.handleMouse(typedMessage)
 	case tea.KeyMsg:
-		if time.Since(m.messageTime) > 3*time.Second {
+		if time.Since(m.messageTime) > m.messageTimeout {
 			m.message = ""
 		}
 
@@ -28,10 +89,24 @@ func (m Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleDeepSearchMode(typedMessage)
 		case ModeRename:
 			return m.handleRenameMode(typedMessage)
+		case ModeTag:
+			return m.handleTagMode(typedMessage)
+		case ModeNote:
+			return m.handleNoteMode(typedMessage)
 		case ModeConfirm:
 			return m.handleConfirmMode(typedMessage)
 		case ModeReassign:
 			return m.handleReassignMode(typedMessage)
+		case ModeRelocate:
+			return m.handleRelocateMode(typedMessage)
+		case ModeRoot:
+			return m.handleRootMode(typedMessage)
+		case ModePalette:
+			return m.handlePaletteMode(typedMessage)
+		case ModePlan:
+			return m.handlePlanMode(typedMessage)
+		case ModeDoctor:
+			return m.handleDoctorMode(typedMessage)
 		default:
 			return m.handleNormalMode(typedMessage)
 		}
@@ -41,210 +116,11 @@ func (m Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleNormalMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch {
-	case key.Matches(keyMessage, m.keys.Quit):
-		return m, tea.Quit
-	case key.Matches(keyMessage, m.keys.Help):
-		m.showHelp = !m.showHelp
-	case key.Matches(keyMessage, m.keys.Preview):
-		m.showPreview = !m.showPreview
-		m.previewFocus = false
-		m.previewScroll = 0
-
-		m.invalidatePreviewCache()
-	case key.Matches(keyMessage, m.keys.Up):
-		if m.showPreview && m.previewFocus {
-			m.previewScroll -= 1
-
-			m.clampPreviewScroll()
-		} else if m.cursor > 0 {
-			m.cursor -= 1
-
-			m.ensureVisible()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Down):
-		if m.showPreview && m.previewFocus {
-			m.previewScroll += 1
-
-			m.clampPreviewScroll()
-		} else if m.cursor < len(m.filtered)-1 {
-			m.cursor += 1
-
-			m.ensureVisible()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.HalfUp):
-		if m.showPreview && m.previewFocus {
-			m.previewScroll -= 10
-
-			m.clampPreviewScroll()
-		} else {
-			m.cursor = max(0, m.cursor-10)
-
-			m.ensureVisible()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.HalfDown):
-		if m.showPreview && m.previewFocus {
-			m.previewScroll += 10
-
-			m.clampPreviewScroll()
-		} else {
-			m.cursor = min(len(m.filtered)-1, m.cursor+10)
-
-			m.ensureVisible()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Top):
-		if m.showPreview && m.previewFocus {
-			m.previewScroll = 0
-		} else {
-			m.cursor = 0
-
-			m.ensureVisible()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Bottom):
-		if m.showPreview && m.previewFocus {
-			m.previewScroll = 99999
-
-			m.clampPreviewScroll()
-		} else {
-			m.cursor = max(0, len(m.filtered)-1)
+	for _, name := range ui.ListBindingNames() {
+		if binding, _ := m.keys.Binding(name); key.Matches(keyMessage, binding) {
+			command := listActions[name](&m)
 
-			m.ensureVisible()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Tab):
-		if m.showPreview {
-			m.previewFocus = !m.previewFocus
-		} else {
-			if m.tab == TabSessions {
-				m.tab = TabTrash
-			} else {
-				m.tab = TabSessions
-			}
-
-			m.cursor = 0
-			m.offset = 0
-
-			m.updateFiltered()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Left):
-		if m.tab != TabSessions {
-			m.tab = TabSessions
-			m.cursor = 0
-			m.offset = 0
-
-			m.updateFiltered()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Right):
-		if m.tab != TabTrash {
-			m.tab = TabTrash
-			m.cursor = 0
-			m.offset = 0
-
-			m.updateFiltered()
-			m.invalidatePreviewCache()
-		}
-	case key.Matches(keyMessage, m.keys.Search):
-		m.mode = ModeSearch
-
-		m.searchInput.Focus()
-
-		return m, textinput.Blink
-	case key.Matches(keyMessage, m.keys.Delete):
-		if len(m.filtered) > 0 {
-			if m.tab == TabTrash {
-				m.confirmAction = ConfirmPermanentDelete
-			} else {
-				m.confirmAction = ConfirmDelete
-			}
-
-			m.mode = ModeConfirm
-		}
-	case key.Matches(keyMessage, m.keys.Restore):
-		if len(m.filtered) > 0 && m.tab == TabTrash {
-			m.confirmAction = ConfirmRestore
-			m.mode = ModeConfirm
-		}
-	case key.Matches(keyMessage, m.keys.Rename):
-		if len(m.filtered) > 0 {
-			session := &m.filtered[m.cursor]
-
-			m.renameInput.SetValue(session.Summary)
-			m.renameInput.Focus()
-
-			m.mode = ModeRename
-
-			return m, textinput.Blink
-		}
-	case key.Matches(keyMessage, m.keys.Reassign):
-		if len(m.filtered) > 0 {
-			session := &m.filtered[m.cursor]
-
-			m.reassignInput.SetValue(session.ProjectPath)
-			m.reassignInput.Focus()
-
-			m.reassignAll = false
-			m.mode = ModeReassign
-
-			return m, textinput.Blink
-		}
-	case key.Matches(keyMessage, m.keys.ReassignAll):
-		if len(m.filtered) > 0 {
-			session := &m.filtered[m.cursor]
-
-			m.reassignInput.SetValue(session.ProjectPath)
-			m.reassignInput.Focus()
-
-			m.reassignAll = true
-			m.mode = ModeReassign
-
-			return m, textinput.Blink
-		}
-	case key.Matches(keyMessage, m.keys.Clear):
-		if m.tab == TabTrash {
-			m.confirmAction = ConfirmEmptyTrash
-			m.mode = ModeConfirm
-		}
-	case key.Matches(keyMessage, m.keys.DeepSearch):
-		m.mode = ModeDeepSearch
-
-		m.deepSearchInput.SetValue(m.deepSearchQuery)
-		m.deepSearchInput.Focus()
-
-		return m, textinput.Blink
-	case key.Matches(keyMessage, m.keys.NextMatch):
-		if m.showPreview && m.previewFocus && len(m.previewSearchMatches) > 0 {
-			m.previewSearchIndex = (m.previewSearchIndex + 1) % len(m.previewSearchMatches)
-
-			m.scrollToPreviewMatch()
-		} else if len(m.deepSearchResults) > 0 {
-			m.deepSearchIndex = (m.deepSearchIndex + 1) % len(m.deepSearchResults)
-
-			m.jumpToSearchResult()
-		}
-	case key.Matches(keyMessage, m.keys.PrevMatch):
-		if m.showPreview && m.previewFocus && len(m.previewSearchMatches) > 0 {
-			m.previewSearchIndex -= 1
-
-			if m.previewSearchIndex < 0 {
-				m.previewSearchIndex = len(m.previewSearchMatches) - 1
-			}
-
-			m.scrollToPreviewMatch()
-		} else if len(m.deepSearchResults) > 0 {
-			m.deepSearchIndex -= 1
-
-			if m.deepSearchIndex < 0 {
-				m.deepSearchIndex = len(m.deepSearchResults) - 1
-			}
-
-			m.jumpToSearchResult()
+			return m, command
 		}
 	}
 
@@ -358,14 +234,12 @@ func (m Model) handleRenameMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
 				session := m.selectedSession()
 
 				if session != nil {
-					if renameError := claude.RenameSession(session, newName); renameError != nil {
-						m.setMessage(fmt.Sprintf("Error: %v", renameError))
-					} else {
-						session.Summary = newName
-
-						m.updateFilteredFromOriginal()
-						m.setMessage("Renamed")
-					}
+					m.mode = ModeNormal
+
+					m.renameInput.Blur()
+					m.runPlan(claude.PlanRenameSession(session, newName), func(int) string { return "Renamed" })
+
+					return m, nil
 				}
 			}
 		}
@@ -384,6 +258,76 @@ func (m Model) handleRenameMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, command
 }
 
+func (m Model) handleTagMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape):
+		m.mode = ModeNormal
+
+		m.tagInput.Blur()
+
+		return m, nil
+	case key.Matches(keyMessage, m.keys.Enter):
+		m.mode = ModeNormal
+
+		m.tagInput.Blur()
+
+		if session := m.selectedSession(); session != nil {
+			tags := claude.ParseTags(m.tagInput.Value())
+
+			message := "Tagged " + strings.Join(tags, ", ")
+
+			if len(tags) == 0 {
+				message = "Tags cleared"
+			}
+
+			m.runPlan(claude.PlanSetTags(session, tags), func(int) string { return message })
+		}
+
+		return m, nil
+	}
+
+	var command tea.Cmd
+
+	m.tagInput, command = m.tagInput.Update(keyMessage)
+
+	return m, command
+}
+
+func (m Model) handleNoteMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape):
+		m.mode = ModeNormal
+
+		m.noteInput.Blur()
+
+		return m, nil
+	case key.Matches(keyMessage, m.keys.SaveNote):
+		m.mode = ModeNormal
+
+		m.noteInput.Blur()
+
+		if session := m.selectedSession(); session != nil {
+			note := strings.TrimSpace(m.noteInput.Value())
+
+			message := "Note saved"
+
+			if note == "" {
+				message = "Note removed"
+			}
+
+			m.runPlan(claude.PlanSetNote(session, note), func(int) string { return message })
+		}
+
+		return m, nil
+	}
+
+	var command tea.Cmd
+
+	m.noteInput, command = m.noteInput.Update(keyMessage)
+
+	return m, command
+}
+
 func (m Model) handleReassignMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(keyMessage, m.keys.Escape):
@@ -391,105 +335,287 @@ func (m Model) handleReassignMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		m.reassignInput.Blur()
 
+		return m, nil
+	case key.Matches(keyMessage, m.keys.Tab):
+		completed, completions := completePath(m.reassignInput.Value())
+
+		m.reassignInput.SetValue(completed)
+		m.reassignInput.CursorEnd()
+
+		m.reassignCompletions = completions
+		m.reassignConfirmMissing = false
+		m.reassignTarget = m.inspectReassignTarget()
+
 		return m, nil
 	case key.Matches(keyMessage, m.keys.Enter):
-		if len(m.filtered) > 0 {
-			newPath := m.reassignInput.Value()
+		if m.reassignInput.Value() == "" {
+			m.mode = ModeNormal
 
-			if newPath != "" {
-				session := m.selectedSession()
+			m.reassignInput.Blur()
 
-				if session != nil {
-					oldPath := session.ProjectPath
-
-					if m.reassignAll {
-						count, reassignError := claude.ReassignProjectPath(oldPath, newPath)
-
-						if reassignError != nil {
-							m.setMessage(fmt.Sprintf("Error: %v", reassignError))
-						} else {
-							m.setMessage(fmt.Sprintf("Reassigned %d sessions", count))
-							m.reloadSessions()
-						}
-					} else {
-						if reassignError := claude.ReassignSessionPath(session, newPath); reassignError != nil {
-							m.setMessage(fmt.Sprintf("Error: %v", reassignError))
-						} else {
-							m.setMessage("Reassigned")
-							m.reloadSessions()
-						}
-					}
-				}
-			}
+			return m, nil
+		}
+
+		target := m.inspectReassignTarget()
+		m.reassignTarget = target
+
+		if target.exists && !target.isDirectory {
+			m.setMessage("Target is not a directory")
+
+			return m, nil
+		}
+
+		if !target.exists && !m.reassignConfirmMissing {
+			m.reassignConfirmMissing = true
+
+			return m, nil
 		}
 
 		m.mode = ModeNormal
 
 		m.reassignInput.Blur()
 
+		if m.reassignAll {
+			plan, planError := claude.PlanReassignProjectPath(m.reassignFrom, target.path)
+
+			if planError != nil {
+				m.setMessage(fmt.Sprintf("Error: %v", planError))
+			} else {
+				m.runPlan(plan, func(appliedCount int) string { return fmt.Sprintf("Reassigned %d sessions", appliedCount) })
+			}
+		} else if session := m.selectedSession(); session != nil {
+			m.runPlan(claude.PlanReassignSessionPath(session, target.path), func(int) string { return "Reassigned" })
+		}
+
 		return m, nil
 	}
 
 	var command tea.Cmd
 
+	previousValue := m.reassignInput.Value()
 	m.reassignInput, command = m.reassignInput.Update(keyMessage)
 
+	if m.reassignInput.Value() != previousValue {
+		m.reassignCompletions = nil
+		m.reassignConfirmMissing = false
+		m.reassignTarget = m.inspectReassignTarget()
+	}
+
 	return m, command
 }
 
+func (m Model) handleRelocateMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape):
+		m.mode = ModeNormal
+		m.relocations = nil
+	case key.Matches(keyMessage, m.keys.Up):
+		m.relocationCursor = max(0, m.relocationCursor-1)
+	case key.Matches(keyMessage, m.keys.Down):
+		m.relocationCursor = min(len(m.relocations)-1, m.relocationCursor+1)
+	case key.Matches(keyMessage, m.keys.Collapse):
+		m.relocationSelected[m.relocationCursor] = !m.relocationSelected[m.relocationCursor]
+	case key.Matches(keyMessage, m.keys.Confirm):
+		plan := &claude.Plan{Description: "Relocate missing project folders", ContinueOnError: true}
+
+		var failures []string
+
+		for proposalIndex, proposal := range m.relocations {
+			if !m.relocationSelected[proposalIndex] {
+				continue
+			}
+
+			proposalPlan, planError := claude.PlanReassignProjectPath(proposal.OldPath, proposal.NewPath)
+
+			if planError != nil {
+				failures = append(failures, proposal.OldPath)
+
+				continue
+			}
+
+			plan.Merge(proposalPlan)
+		}
+
+		m.mode = ModeNormal
+		m.relocations = nil
+
+		if len(failures) > 0 {
+			m.setMessage("Could not plan relocation for " + strings.Join(failures, ", "))
+
+			break
+		}
+
+		m.runPlan(plan, func(appliedCount int) string { return fmt.Sprintf("Reassigned %d sessions", appliedCount) })
+	}
+
+	return m, nil
+}
+
+func (m Model) handleRootMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape):
+		m.mode = ModeNormal
+		m.rootTargets = nil
+	case key.Matches(keyMessage, m.keys.Up):
+		m.rootCursor = max(0, m.rootCursor-1)
+	case key.Matches(keyMessage, m.keys.Down):
+		m.rootCursor = min(len(m.rootTargets)-1, m.rootCursor+1)
+	case key.Matches(keyMessage, m.keys.Enter):
+		destination := m.rootTargets[m.rootCursor]
+		m.mode = ModeNormal
+		m.rootTargets = nil
+
+		if session := m.cursorSession(); session != nil {
+			m.transferToRoot(session, destination, m.rootCopy)
+		}
+	}
+
+	return m, nil
+}
+
 func (m Model) handleConfirmMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case key.Matches(keyMessage, m.keys.Escape), keyMessage.String() == "n", keyMessage.String() == "N":
+	case key.Matches(keyMessage, m.keys.Escape, m.keys.Deny):
 		m.mode = ModeNormal
 		m.confirmAction = ConfirmNone
 
 		return m, nil
 	case key.Matches(keyMessage, m.keys.Confirm):
-		return m.executeConfirmedAction()
+		return m.executeConfirmedAction(false)
+	case key.Matches(keyMessage, m.keys.IncludePinned) && m.confirmPinnedCount() > 0:
+		return m.executeConfirmedAction(true)
 	}
 
 	return m, nil
 }
 
-func (m Model) executeConfirmedAction() (tea.Model, tea.Cmd) {
+func (m *Model) confirmPinnedCount() int {
+	var sessions []claude.Session
+
 	switch m.confirmAction {
+	case ConfirmDeleteProject, ConfirmPermanentDeleteProject:
+		sessions = m.groupSessions(m.confirmGroup)
+	case ConfirmEmptyTrash:
+		sessions = m.tabSessions()
+	}
+
+	var pinnedCount int
+
+	for _, session := range sessions {
+		if session.Pinned {
+			pinnedCount += 1
+		}
+	}
+
+	return pinnedCount
+}
+
+func (m Model) executeConfirmedAction(includePinned bool) (tea.Model, tea.Cmd) {
+	confirmAction := m.confirmAction
+	m.mode = ModeNormal
+	m.confirmAction = ConfirmNone
+
+	switch confirmAction {
 	case ConfirmDelete:
 		if session := m.selectedSession(); session != nil {
-			if deleteError := claude.MoveToTrash(session); deleteError != nil {
-				m.setMessage(fmt.Sprintf("Error: %v", deleteError))
-			} else {
-				m.reloadSessions()
-			}
+			m.runPlan(claude.PlanMoveToTrash(session), func(int) string { return "Moved to the Bin" })
 		}
 	case ConfirmRestore:
 		if session := m.selectedSession(); session != nil {
-			if restoreError := claude.RestoreFromTrash(session); restoreError != nil {
-				m.setMessage(fmt.Sprintf("Error: %v", restoreError))
-			} else {
-				m.setMessage("Restored")
-				m.reloadSessions()
-			}
+			m.runPlan(claude.PlanRestoreFromTrash(session), func(int) string { return "Restored" })
 		}
 	case ConfirmPermanentDelete:
 		if session := m.selectedSession(); session != nil {
-			if deleteError := claude.PermanentlyDelete(session); deleteError != nil {
-				m.setMessage(fmt.Sprintf("Error: %v", deleteError))
-			} else {
-				m.setMessage("Permanently deleted")
-				m.reloadSessions()
+			m.runPlan(claude.PlanPermanentlyDelete(session), func(int) string { return "Permanently deleted" })
+		}
+	case ConfirmDeleteProject, ConfirmRestoreProject, ConfirmPermanentDeleteProject:
+		if m.confirmGroup != nil {
+			sessions := m.groupSessions(m.confirmGroup)
+			plan := &claude.Plan{Description: "Project " + m.confirmGroup.name}
+
+			var describe func(appliedCount int) string
+
+			for sessionIndex := range sessions {
+				if sessions[sessionIndex].Pinned && !includePinned && confirmAction != ConfirmRestoreProject {
+					continue
+				}
+
+				switch confirmAction {
+				case ConfirmDeleteProject:
+					plan.Merge(claude.PlanMoveToTrash(&sessions[sessionIndex]))
+				case ConfirmRestoreProject:
+					plan.Merge(claude.PlanRestoreFromTrash(&sessions[sessionIndex]))
+				case ConfirmPermanentDeleteProject:
+					plan.Merge(claude.PlanPermanentlyDelete(&sessions[sessionIndex]))
+				}
 			}
+
+			switch confirmAction {
+			case ConfirmDeleteProject:
+				describe = func(appliedCount int) string { return fmt.Sprintf("Moved %d sessions to the Bin", appliedCount) }
+			case ConfirmRestoreProject:
+				describe = func(appliedCount int) string { return fmt.Sprintf("Restored %d sessions", appliedCount) }
+			case ConfirmPermanentDeleteProject:
+				describe = func(appliedCount int) string { return fmt.Sprintf("Permanently deleted %d sessions", appliedCount) }
+			}
+
+			m.confirmGroup = nil
+
+			m.runPlan(plan, describe)
 		}
 	case ConfirmEmptyTrash:
-		if emptyError := claude.EmptyTrash(); emptyError != nil {
-			m.setMessage(fmt.Sprintf("Error: %v", emptyError))
-		} else {
-			m.setMessage("Bin emptied")
-			m.reloadSessions()
-		}
+		m.runPlan(claude.PlanEmptyTrash(includePinned), func(int) string { return "Bin emptied" })
 	}
 
-	m.mode = ModeNormal
-	m.confirmAction = ConfirmNone
+	return m, nil
+}
+
+func (m Model) handlePlanMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape, m.keys.Deny):
+		m.mode = ModeNormal
+		m.pendingPlan = nil
+
+		m.setMessage("Plan discarded")
+	case key.Matches(keyMessage, m.keys.Up):
+		m.planScroll = max(0, m.planScroll-1)
+	case key.Matches(keyMessage, m.keys.Down):
+		m.planScroll = max(0, min(len(m.planLines())-planVisibleLines, m.planScroll+1))
+	case key.Matches(keyMessage, m.keys.Confirm):
+		plan := m.pendingPlan
+		m.mode = ModeNormal
+		m.pendingPlan = nil
+
+		m.applyPlan(plan, m.pendingPlanDescribe)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleDoctorMode(keyMessage tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMessage, m.keys.Escape), key.Matches(keyMessage, m.keys.Quit):
+		m.mode = ModeNormal
+		m.doctorReport = nil
+	case key.Matches(keyMessage, m.keys.Up):
+		m.doctorScroll = max(0, m.doctorScroll-1)
+	case key.Matches(keyMessage, m.keys.Down):
+		m.doctorScroll = max(0, min(len(m.doctorReport.Problems)-1, m.doctorScroll+1))
+	case key.Matches(keyMessage, m.keys.Fix):
+		report := m.doctorReport
+		m.mode = ModeNormal
+		m.doctorReport = nil
+
+		if report.FixableCount() == 0 {
+			m.setMessage("Nothing to fix")
+
+			break
+		}
+
+		m.runPlan(claude.PlanDoctorFix(report), func(appliedCount int) string {
+			return fmt.Sprintf("Applied %d repairs", appliedCount)
+		})
+	}
 
 	return m, nil
 }
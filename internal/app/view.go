@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"github.com/Fuwn/faustus/internal/claude"
 	"github.com/Fuwn/faustus/internal/ui"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
+	"slices"
 	"strings"
 	"time"
 )
@@ -16,6 +18,54 @@ func (m Model) View() string {
 
 	var builder strings.Builder
 
+	builder.WriteString(m.renderTop())
+
+	content := m.renderList()
+
+	if m.showPreview {
+		content = m.renderSplitView()
+	}
+
+	if m.showTagSidebar {
+		content = lipgloss.JoinHorizontal(lipgloss.Top, m.renderTagSidebar(), " ", content)
+	}
+
+	builder.WriteString(content)
+
+	if m.message != "" && time.Since(m.messageTime) < m.messageTimeout {
+		builder.WriteString("\n")
+		builder.WriteString(ui.StatusBarStyle.Render(m.message))
+	}
+
+	if m.showHelp {
+		builder.WriteString("\n")
+		builder.WriteString(m.renderHelp())
+	} else {
+		builder.WriteString("\n")
+
+		previewHint := ""
+
+		if m.showPreview {
+			if m.previewFocus {
+				previewHint = " • Preview focused"
+			} else {
+				previewHint = " • Tab to focus preview"
+			}
+		}
+
+		footer := fmt.Sprintf("%s Help • %s Commands • %s Navigate • %s/%s Tabs • %s Filter • %s Preview",
+			helpKey(m.keys.Help), helpKey(m.keys.Palette), m.navigationKeys(), helpKey(m.keys.Left), helpKey(m.keys.Right),
+			helpKey(m.keys.Search), helpKey(m.keys.Preview)) + previewHint
+
+		builder.WriteString(ui.HelpStyle.MaxWidth(m.width).Render(footer))
+	}
+
+	return builder.String()
+}
+
+func (m Model) renderTop() string {
+	var builder strings.Builder
+
 	builder.WriteString(m.renderHeader())
 	builder.WriteString("\n")
 	builder.WriteString(m.renderTabs())
@@ -45,6 +95,16 @@ func (m Model) View() string {
 		builder.WriteString("\n")
 	}
 
+	if m.mode == ModeTag {
+		builder.WriteString(m.renderTag())
+		builder.WriteString("\n")
+	}
+
+	if m.mode == ModeNote {
+		builder.WriteString(m.renderNote())
+		builder.WriteString("\n")
+	}
+
 	if m.mode == ModeReassign {
 		builder.WriteString(m.renderReassign())
 		builder.WriteString("\n")
@@ -55,66 +115,32 @@ func (m Model) View() string {
 		builder.WriteString("\n\n")
 	}
 
-	if m.showPreview {
-		builder.WriteString(m.renderSplitView())
-	} else {
-		builder.WriteString(m.renderList())
+	if m.mode == ModeRelocate {
+		builder.WriteString(m.renderRelocate())
+		builder.WriteString("\n\n")
 	}
 
-	if m.message != "" && time.Since(m.messageTime) < 3*time.Second {
-		builder.WriteString("\n")
-		builder.WriteString(ui.StatusBarStyle.Render(m.message))
+	if m.mode == ModeRoot {
+		builder.WriteString(m.renderRootPicker())
+		builder.WriteString("\n\n")
 	}
 
-	if m.showHelp {
-		builder.WriteString("\n")
-		builder.WriteString(m.renderHelp())
-	} else {
-		builder.WriteString("\n")
-
-		previewHint := ""
-
-		if m.showPreview {
-			if m.previewFocus {
-				previewHint = " • Preview focused"
-			} else {
-				previewHint = " • Tab to focus preview"
-			}
-		}
-
-		builder.WriteString(ui.HelpStyle.Render("? Help • j/k Navigate • h/l Tabs • / Filter • p Preview" + previewHint))
+	if m.mode == ModePalette {
+		builder.WriteString(m.renderPalette())
+		builder.WriteString("\n\n")
 	}
 
-	return builder.String()
-}
-
-func (m Model) renderSplitView() string {
-	listWidth := m.listWidth()
-	previewWidth := m.previewWidth()
-	listHeight := m.listHeight()
-	listContent := m.renderListCompact(listWidth-2, listHeight-2)
-	previewContent := m.renderPreview(previewWidth-2, listHeight-2)
-
-	var listStyle, previewStyle lipgloss.Style
-
-	if m.previewFocus {
-		listStyle = ui.ListBoxStyle
-		previewStyle = ui.PreviewFocusedStyle
-	} else {
-		listStyle = ui.ListBoxFocusedStyle
-		previewStyle = ui.PreviewStyle
+	if m.mode == ModePlan {
+		builder.WriteString(m.renderPlan())
+		builder.WriteString("\n\n")
 	}
 
-	listBox := listStyle.
-		Width(listWidth).
-		Height(listHeight).
-		Render(listContent)
-	previewBox := previewStyle.
-		Width(previewWidth).
-		Height(listHeight).
-		Render(previewContent)
+	if m.mode == ModeDoctor {
+		builder.WriteString(m.renderDoctor())
+		builder.WriteString("\n\n")
+	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, listBox, " ", previewBox)
+	return builder.String()
 }
 
 func (m Model) renderListCompact(width, height int) string {
@@ -132,16 +158,27 @@ func (m Model) renderListCompact(width, height int) string {
 
 	var builder strings.Builder
 
-	for index := m.offset; index < min(m.offset+height, len(m.filtered)); index++ {
-		session := m.filtered[index]
+	for index := m.offset; index < min(m.offset+height, len(m.rows)); index++ {
+		row := m.rows[index]
 		isSelected := index == m.cursor
 
-		builder.WriteString(m.renderSessionCompact(&session, isSelected, width))
+		if row.isHeader() {
+			builder.WriteString(m.renderGroupHeaderCompact(row.group, isSelected, width))
+		} else {
+			session := m.filtered[row.sessionIndex]
+
+			if row.group != nil {
+				builder.WriteString("  ")
+			}
+
+			builder.WriteString(m.renderSessionCompact(&session, isSelected, width))
+		}
+
 		builder.WriteString("\n")
 	}
 
-	if len(m.filtered) > height {
-		indicator := fmt.Sprintf("[%d/%d]", m.cursor+1, len(m.filtered))
+	if len(m.rows) > height {
+		indicator := fmt.Sprintf("[%d/%d]", m.cursor+1, len(m.rows))
 
 		builder.WriteString(ui.MetaStyle.Render(indicator))
 	}
@@ -177,6 +214,10 @@ func (m Model) renderSessionCompact(session *claude.Session, isSelected bool, ma
 }
 
 func (m Model) renderPreview(width, height int) string {
+	if group := m.cursorGroup(); group != nil {
+		return m.renderGroupPreview(group, width)
+	}
+
 	preview := m.preview()
 
 	if preview == nil {
@@ -189,15 +230,8 @@ func (m Model) renderPreview(width, height int) string {
 
 	var lines []string
 
-	if m.cursor < len(m.filtered) {
-		session := &m.filtered[m.cursor]
-		header := ui.PreviewHeaderStyle.Render(truncate(session.Summary, width-4))
-		lines = append(lines, header)
-		meta := ui.MetaStyle.Render(fmt.Sprintf("%s • %s • %d messages",
-			session.ProjectName, formatTime(session.Modified), len(preview.Messages)))
-		lines = append(lines, meta)
-		lines = append(lines, ui.PreviewDividerStyle.Render(strings.Repeat("─", width-4)))
-		lines = append(lines, "")
+	if session := m.cursorSession(); session != nil {
+		lines = append(lines, m.previewHeaderLines(session, preview, width)...)
 	}
 
 	for messageIndex, previewMessage := range preview.Messages {
@@ -247,16 +281,16 @@ func (m Model) renderPreview(width, height int) string {
 		}
 
 		lines = append(lines, roleStyle.Render(prefix+":")+matchIndicator)
-		content := previewMessage.Content
+		query := ""
 
-		if m.previewSearchQuery != "" && isMatch {
-			content = highlightMatches(content, m.previewSearchQuery)
+		if isMatch {
+			query = m.previewSearchQuery
 		}
 
-		wrapped := wrapText(content, width-6)
+		wrapped := wrapText(previewMessage.Content, width-6)
 
 		for _, line := range strings.Split(wrapped, "\n") {
-			lines = append(lines, "  "+contentStyle.Render(line))
+			lines = append(lines, "  "+highlightMatches(line, query, contentStyle))
 		}
 
 		lines = append(lines, "")
@@ -292,6 +326,71 @@ func (m Model) renderPreview(width, height int) string {
 	return strings.Join(lines, "\n")
 }
 
+func (m Model) previewHeaderLines(session *claude.Session, preview *claude.PreviewContent, width int) []string {
+	var lines []string
+
+	header := ui.PreviewHeaderStyle.Render(truncate(session.Summary, width-4))
+	lines = append(lines, header)
+	projectName := session.ProjectName
+
+	if claude.HasMultipleRoots() {
+		projectName = session.Root + " • " + projectName
+	}
+
+	meta := ui.MetaStyle.Render(fmt.Sprintf("%s • %s • %d messages",
+		projectName, formatTime(session.Modified), len(preview.Messages)))
+	lines = append(lines, meta)
+
+	if gitInfo := claude.CachedSessionGitInfo(session); gitInfo.IsRepository {
+		gitLine := ""
+
+		if session.GitBranch != "" {
+			gitLine = "⎇ " + session.GitBranch
+
+			if gitInfo.BranchState == claude.BranchGone || gitInfo.BranchState == claude.BranchMerged {
+				gitLine += " (" + gitInfo.BranchState.String() + ")"
+			}
+		}
+
+		if gitInfo.RemoteURL != "" {
+			if gitLine != "" {
+				gitLine += " • "
+			}
+
+			gitLine += gitInfo.RemoteURL
+		}
+
+		if gitLine != "" {
+			lines = append(lines, ui.MetaStyle.Render(truncate(gitLine, width-4)))
+		}
+
+		if commits := m.cachedPreviewCommits(session); len(commits) > 0 {
+			lines = append(lines, ui.MetaStyle.Render(fmt.Sprintf("%d commits during session:", len(commits))))
+
+			for _, commit := range commits[:min(5, len(commits))] {
+				lines = append(lines, "  "+ui.CommitHashStyle.Render(commit.Hash[:min(7, len(commit.Hash))])+" "+
+					ui.MetaStyle.Render(truncate(commit.Subject, width-14)))
+			}
+		}
+	}
+
+	if session.Note != "" {
+		prefix := "📝 "
+
+		for _, noteParagraph := range strings.Split(session.Note, "\n") {
+			for _, noteLine := range strings.Split(wrapText(noteParagraph, width-7), "\n") {
+				lines = append(lines, prefix+ui.NoteStyle.Render(noteLine))
+				prefix = "   "
+			}
+		}
+	}
+
+	lines = append(lines, ui.PreviewDividerStyle.Render(strings.Repeat("─", max(0, width-4))))
+	lines = append(lines, "")
+
+	return lines
+}
+
 func (m Model) renderHeader() string {
 	logo := ui.LogoStyle.Render("🛎️ Faustus")
 	subtitle := ui.MetaStyle.Render(" • Session Manager for Claude Code")
@@ -305,7 +404,7 @@ func (m Model) renderHeader() string {
 	return logo + subtitle + strings.Repeat(" ", gap) + count
 }
 
-func (m Model) renderTabs() string {
+func (m Model) renderTabLabels() (string, string) {
 	var sessionsCount, trashCount int
 
 	for _, session := range m.sessions {
@@ -320,10 +419,34 @@ func (m Model) renderTabs() string {
 	binTab := fmt.Sprintf("Bin (%d)", trashCount)
 
 	if m.tab == TabSessions {
-		return ui.ActiveTabStyle.Render("● "+sessionsTab) + "    " + ui.TabStyle.Render(binTab)
+		return ui.ActiveTabStyle.Render("● " + sessionsTab), ui.TabStyle.Render(binTab)
 	}
 
-	return ui.TabStyle.Render(sessionsTab) + "    " + ui.ActiveTabStyle.Render("● "+binTab)
+	return ui.TabStyle.Render(sessionsTab), ui.ActiveTabStyle.Render("● " + binTab)
+}
+
+func (m Model) renderTabs() string {
+	sessionsTab, binTab := m.renderTabLabels()
+	tabs := sessionsTab + tabGap + binTab
+
+	direction := "↑"
+
+	if m.sortDescending {
+		direction = "↓"
+	}
+
+	sortIndicator := ui.MetaStyle.Render("Sorted by " + m.sortField.String() + " " + direction)
+
+	if m.dryRun {
+		sortIndicator = ui.WarningStyle.Render("DRY RUN") + ui.MetaStyle.Render(" • ") + sortIndicator
+	}
+	gap := m.width - lipgloss.Width(tabs) - lipgloss.Width(sortIndicator) - 2
+
+	if gap < 1 {
+		return tabs
+	}
+
+	return tabs + strings.Repeat(" ", gap) + sortIndicator
 }
 
 func (m Model) renderSearch() string {
@@ -344,6 +467,62 @@ func (m Model) renderRename() string {
 	return ui.SearchInputStyle.Render("✏️  " + m.renameInput.View())
 }
 
+func (m Model) renderTag() string {
+	return ui.SearchInputStyle.Render("🏷  " + m.tagInput.View())
+}
+
+func (m Model) renderNote() string {
+	return ui.SearchInputStyle.Render("📝 Note\n"+m.noteInput.View()) + "\n" +
+		ui.HelpStyle.Render(fmt.Sprintf("  %s save • %s cancel • empty to remove", helpKey(m.keys.SaveNote),
+			helpKey(m.keys.Escape)))
+}
+
+func (m Model) renderTagChips(tags []string) string {
+	chips := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		chips = append(chips, ui.TagStyle(tag).Render(tag))
+	}
+
+	return strings.Join(chips, " ")
+}
+
+func (m Model) renderTagSidebar() string {
+	tagCounts, untaggedCount := claude.CountTags(m.tabSessions())
+	activeTags := m.activeTagFilters()
+	height := m.contentHeight()
+	lines := []string{ui.HeaderStyle.Render("Tags"), ""}
+
+	if len(tagCounts) == 0 {
+		lines = append(lines, ui.MetaStyle.Render("No tags yet"), ui.MetaStyle.Render("# to add some"))
+	}
+
+	for _, tagCount := range tagCounts {
+		if len(lines) >= height-3 {
+			lines = append(lines, ui.MetaStyle.Render(fmt.Sprintf("… %d more", len(tagCounts)-len(lines)+2)))
+
+			break
+		}
+
+		marker := "  "
+
+		if slices.Contains(activeTags, tagCount.Tag) {
+			marker = ui.CursorStyle.Render("▸ ")
+		}
+
+		count := fmt.Sprintf(" %d", tagCount.Count)
+		chip := ui.TagStyle(tagCount.Tag).Render(truncate(tagCount.Tag, tagSidebarWidth-8-len(count)))
+		lines = append(lines, marker+chip+ui.MetaStyle.Render(count))
+	}
+
+	lines = append(lines, "", ui.MetaStyle.Render(fmt.Sprintf("  untagged %d", untaggedCount)))
+
+	return ui.ListBoxStyle.
+		Width(tagSidebarWidth).
+		Height(height).
+		Render(strings.Join(lines, "\n"))
+}
+
 func (m Model) renderReassign() string {
 	label := "📁 Reassign folder"
 
@@ -351,7 +530,56 @@ func (m Model) renderReassign() string {
 		label = "📁 Reassign ALL sessions with this folder"
 	}
 
-	return ui.SearchInputStyle.Render(label + ": " + m.reassignInput.View())
+	lines := []string{ui.SearchInputStyle.Render(label + ": " + m.reassignInput.View())}
+
+	if len(m.reassignCompletions) > 0 {
+		completions := m.reassignCompletions
+
+		if len(completions) > 8 {
+			completions = append(completions[:8:8], fmt.Sprintf("… %d more", len(m.reassignCompletions)-8))
+		}
+
+		lines = append(lines, ui.HelpStyle.Render("  "+strings.Join(completions, "  ")))
+	}
+
+	target := m.reassignTarget
+
+	var status string
+
+	switch {
+	case !target.exists:
+		status = ui.MissingStyle.Render("Does not exist")
+	case !target.isDirectory:
+		status = ui.MissingStyle.Render("Not a directory")
+	case target.gitInfo.IsRepository:
+		status = ui.SuccessStyle.Render("Exists • git repository")
+	default:
+		status = ui.SuccessStyle.Render("Exists")
+	}
+
+	sessionWord := "sessions"
+
+	if target.affectedCount == 1 {
+		sessionWord = "session"
+	}
+
+	status += ui.HelpStyle.Render(fmt.Sprintf(" • %d %s will be moved", target.affectedCount, sessionWord))
+
+	if target.existingSessions > 0 {
+		status += ui.WarningStyle.Render(fmt.Sprintf(" • merges into %d existing sessions", target.existingSessions))
+	}
+
+	lines = append(lines, "  "+status)
+
+	if m.reassignConfirmMissing {
+		lines = append(lines, "  "+ui.WarningStyle.Render(fmt.Sprintf("Folder does not exist; press %s again to reassign anyway",
+			helpKey(m.keys.Enter))))
+	} else {
+		lines = append(lines, ui.HelpStyle.Render(fmt.Sprintf("  %s complete • %s reassign • %s cancel",
+			helpKey(m.keys.Tab), helpKey(m.keys.Enter), helpKey(m.keys.Escape))))
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 func (m Model) renderConfirm() string {
@@ -365,14 +593,233 @@ func (m Model) renderConfirm() string {
 	case ConfirmPermanentDelete:
 		confirmMessage = "Delete this session permanently? This cannot be undone."
 	case ConfirmEmptyTrash:
-		confirmMessage = "Empty the Bin? All sessions will be permanently deleted."
+		confirmMessage = "Empty the Bin? Its sessions will be permanently deleted."
+	case ConfirmDeleteProject:
+		confirmMessage = fmt.Sprintf("Move all %d sessions in %s to the Bin?", m.confirmGroup.sessionCount, m.confirmGroup.name)
+	case ConfirmRestoreProject:
+		confirmMessage = fmt.Sprintf("Restore all %d sessions in %s from the Bin?", m.confirmGroup.sessionCount, m.confirmGroup.name)
+	case ConfirmPermanentDeleteProject:
+		confirmMessage = fmt.Sprintf("Delete all %d sessions in %s permanently? This cannot be undone.",
+			m.confirmGroup.sessionCount, m.confirmGroup.name)
 	}
 
-	return ui.ModalStyle.Render(
-		ui.ConfirmStyle.Render(confirmMessage) + "\n\n" +
-			ui.HelpKeyStyle.Render("y") + ui.HelpStyle.Render(" confirm  ") +
-			ui.HelpKeyStyle.Render("n/esc") + ui.HelpStyle.Render(" cancel"),
-	)
+	help := ui.HelpKeyStyle.Render(helpKey(m.keys.Confirm)) + ui.HelpStyle.Render(" confirm  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Deny)+"/"+helpKey(m.keys.Escape)) + ui.HelpStyle.Render(" cancel")
+
+	if pinnedCount := m.confirmPinnedCount(); pinnedCount > 0 {
+		confirmMessage += "\n" + ui.WarningStyle.Render(fmt.Sprintf("%d pinned sessions will be kept", pinnedCount))
+		help += ui.HelpStyle.Render("  ") + ui.HelpKeyStyle.Render(helpKey(m.keys.IncludePinned)) +
+			ui.HelpStyle.Render(" include pinned")
+	}
+
+	return ui.ModalStyle.Render(ui.ConfirmStyle.Render(confirmMessage) + "\n\n" + help)
+}
+
+func (m Model) renderRelocate() string {
+	var builder strings.Builder
+
+	builder.WriteString(ui.ConfirmStyle.Render("Relocate sessions whose project folder no longer exists?"))
+	builder.WriteString("\n\n")
+
+	for proposalIndex, proposal := range m.relocations {
+		cursor := "  "
+
+		if proposalIndex == m.relocationCursor {
+			cursor = ui.CursorStyle.Render("▸ ")
+		}
+
+		checkbox := "[ ] "
+
+		if m.relocationSelected[proposalIndex] {
+			checkbox = "[x] "
+		}
+
+		builder.WriteString(cursor + checkbox + ui.MetaStyle.Render(proposal.OldPath) + " → " +
+			ui.ProjectStyle.Render(proposal.NewPath))
+		builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf(" • %d sessions • %s", proposal.SessionCount, proposal.Reason)))
+
+		if len(proposal.Alternatives) > 0 {
+			builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf(" • %d other candidates", len(proposal.Alternatives))))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(ui.HelpKeyStyle.Render(helpKey(m.keys.Collapse)) + ui.HelpStyle.Render(" toggle  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Confirm)) + ui.HelpStyle.Render(" reassign selected  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Escape)) + ui.HelpStyle.Render(" cancel"))
+
+	return ui.ModalStyle.Render(builder.String())
+}
+
+func (m Model) renderRootPicker() string {
+	var builder strings.Builder
+
+	verb := "Move"
+
+	if m.rootCopy {
+		verb = "Copy"
+	}
+
+	builder.WriteString(ui.ConfirmStyle.Render(verb + " session to which root?"))
+	builder.WriteString("\n\n")
+
+	for rootIndex, root := range m.rootTargets {
+		cursor := "  "
+
+		if rootIndex == m.rootCursor {
+			cursor = ui.CursorStyle.Render("▸ ")
+		}
+
+		builder.WriteString(cursor + ui.RootStyle.Render(root.Label) + " " + ui.MetaStyle.Render(root.Dir))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(ui.HelpKeyStyle.Render(m.navigationKeys()) + ui.HelpStyle.Render(" choose  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Enter)) + ui.HelpStyle.Render(" "+strings.ToLower(verb)+"  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Escape)) + ui.HelpStyle.Render(" cancel"))
+
+	return ui.ModalStyle.Render(builder.String())
+}
+
+func (m Model) renderPalette() string {
+	var builder strings.Builder
+
+	builder.WriteString(m.paletteInput.View())
+	builder.WriteString("\n\n")
+
+	matches := m.paletteMatches()
+	width := min(60, max(30, m.width-12))
+
+	if len(matches) == 0 {
+		builder.WriteString(ui.MutedStyle.Render("No matching commands"))
+		builder.WriteString("\n")
+	}
+
+	cursor := min(m.paletteCursor, max(0, len(matches)-1))
+	start := max(0, min(cursor-paletteVisibleCommands/2, len(matches)-paletteVisibleCommands))
+
+	for commandIndex := start; commandIndex < min(start+paletteVisibleCommands, len(matches)); commandIndex++ {
+		command := matches[commandIndex]
+		keyText := ""
+
+		if len(command.binding.Keys()) > 0 {
+			keyText = helpKey(command.binding)
+		}
+
+		title := truncate(command.title, width-len(keyText)-3)
+		gap := strings.Repeat(" ", max(1, width-lipgloss.Width(title)-lipgloss.Width(keyText)-2))
+
+		if commandIndex == cursor {
+			builder.WriteString(ui.CursorStyle.Render("▸ ") + ui.TitleStyle.Render(title))
+		} else {
+			builder.WriteString("  " + ui.BaseStyle.Render(title))
+		}
+
+		builder.WriteString(gap + ui.HelpKeyStyle.Render(keyText))
+		builder.WriteString("\n")
+	}
+
+	if len(matches) > paletteVisibleCommands {
+		builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf("%d of %d commands", cursor+1, len(matches))))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(ui.HelpKeyStyle.Render(helpKey(m.keys.PaletteDown)+"/"+helpKey(m.keys.PaletteUp)) +
+		ui.HelpStyle.Render(" choose  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Enter)) + ui.HelpStyle.Render(" run  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Escape)) + ui.HelpStyle.Render(" cancel"))
+
+	return ui.ModalStyle.Render(builder.String())
+}
+
+func (m Model) renderPlan() string {
+	var builder strings.Builder
+
+	plan := m.pendingPlan
+	lines := m.planLines()
+
+	builder.WriteString(ui.ConfirmStyle.Render(plan.Description))
+	builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf(" • %d operations • %d steps", len(plan.Operations), plan.StepCount())))
+	builder.WriteString("\n\n")
+
+	for lineIndex := m.planScroll; lineIndex < min(m.planScroll+planVisibleLines, len(lines)); lineIndex++ {
+		line := truncate(lines[lineIndex], m.width-8)
+
+		if strings.HasPrefix(line, "  ") {
+			builder.WriteString(ui.MetaStyle.Render(line))
+		} else {
+			builder.WriteString(ui.ProjectStyle.Render(line))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	if len(lines) > planVisibleLines {
+		builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf("  … lines %d–%d of %d",
+			m.planScroll+1, min(m.planScroll+planVisibleLines, len(lines)), len(lines))))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(ui.HelpKeyStyle.Render(m.navigationKeys()) + ui.HelpStyle.Render(" scroll  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Confirm)) + ui.HelpStyle.Render(" apply  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Escape)) + ui.HelpStyle.Render(" discard"))
+
+	return ui.ModalStyle.Render(builder.String())
+}
+
+func (m Model) renderDoctor() string {
+	var builder strings.Builder
+
+	report := m.doctorReport
+
+	builder.WriteString(ui.ConfirmStyle.Render("Session storage check"))
+	builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf(" • %d directories • %d session files",
+		report.DirectoriesScanned, report.FilesScanned)))
+	builder.WriteString("\n\n")
+
+	if len(report.Problems) == 0 {
+		builder.WriteString(ui.SuccessStyle.Render("No problems found"))
+		builder.WriteString("\n")
+	}
+
+	visibleProblems := planVisibleLines / 3
+
+	for problemIndex := m.doctorScroll; problemIndex < min(m.doctorScroll+visibleProblems, len(report.Problems)); problemIndex++ {
+		problem := report.Problems[problemIndex]
+		target := problem.Path
+
+		if problem.SessionID != "" {
+			target = problem.SessionID + " • " + target
+		}
+
+		builder.WriteString(ui.MissingStyle.Render(string(problem.Kind)) + " " + ui.ProjectStyle.Render(truncate(target, m.width-30)))
+		builder.WriteString("\n")
+		builder.WriteString(ui.MetaStyle.Render("  " + truncate(problem.Explanation, m.width-10)))
+		builder.WriteString("\n")
+	}
+
+	if len(report.Problems) > visibleProblems {
+		builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf("  … problems %d–%d of %d",
+			m.doctorScroll+1, min(m.doctorScroll+visibleProblems, len(report.Problems)), len(report.Problems))))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\n")
+
+	if report.FixableCount() > 0 {
+		builder.WriteString(ui.HelpKeyStyle.Render(helpKey(m.keys.Fix)) +
+			ui.HelpStyle.Render(fmt.Sprintf(" fix %d  ", report.FixableCount())))
+	}
+
+	builder.WriteString(ui.HelpKeyStyle.Render(m.navigationKeys()) + ui.HelpStyle.Render(" scroll  ") +
+		ui.HelpKeyStyle.Render(helpKey(m.keys.Escape)) + ui.HelpStyle.Render(" close"))
+
+	return ui.ModalStyle.Render(builder.String())
 }
 
 func (m Model) renderList() string {
@@ -392,17 +839,29 @@ func (m Model) renderList() string {
 
 	visible := m.visibleItemCount()
 
-	for index := m.offset; index < min(m.offset+visible, len(m.filtered)); index++ {
-		session := m.filtered[index]
+	for index := m.offset; index < min(m.offset+visible, len(m.rows)); index++ {
+		row := m.rows[index]
 		isSelected := index == m.cursor
 
-		builder.WriteString(m.renderSession(&session, isSelected))
+		if row.isHeader() {
+			builder.WriteString(m.renderGroupHeader(row.group, isSelected))
+		} else {
+			session := m.filtered[row.sessionIndex]
+			rendered := m.renderSession(&session, isSelected)
+
+			if row.group != nil {
+				rendered = "  " + strings.ReplaceAll(rendered, "\n", "\n  ")
+			}
+
+			builder.WriteString(rendered)
+		}
+
 		builder.WriteString("\n")
 	}
 
-	if len(m.filtered) > visible {
-		position := float64(m.offset) / float64(len(m.filtered)-visible)
-		indicator := fmt.Sprintf(" [%d-%d of %d]", m.offset+1, min(m.offset+visible, len(m.filtered)), len(m.filtered))
+	if len(m.rows) > visible {
+		position := float64(m.offset) / float64(len(m.rows)-visible)
+		indicator := fmt.Sprintf(" [%d-%d of %d]", m.offset+1, min(m.offset+visible, len(m.rows)), len(m.rows))
 
 		builder.WriteString(ui.MetaStyle.Render(indicator))
 
@@ -436,22 +895,61 @@ func (m Model) renderSession(session *claude.Session, isSelected bool) string {
 		summary = "(No summary)"
 	}
 
+	chips := m.renderTagChips(session.Tags)
+	summaryWidth := m.contentWidth() - 20
+
+	if chips != "" {
+		summaryWidth = max(10, summaryWidth-lipgloss.Width(chips)-1)
+	}
+
 	if isSelected {
-		builder.WriteString(ui.SelectedItemStyle.Render(truncate(summary, m.width-20)))
+		builder.WriteString(ui.SelectedItemStyle.Render(truncate(summary, summaryWidth)))
 	} else {
-		builder.WriteString(ui.TitleStyle.Render(truncate(summary, m.width-20)))
+		builder.WriteString(ui.TitleStyle.Render(truncate(summary, summaryWidth)))
+	}
+
+	if chips != "" {
+		builder.WriteString(" " + chips)
 	}
 
 	builder.WriteString("\n")
 
 	meta := fmt.Sprintf("    %s", ui.ProjectStyle.Render(session.ProjectName))
 
+	if claude.HasMultipleRoots() {
+		meta = "    " + ui.RootStyle.Render(session.Root) + " " + ui.ProjectStyle.Render(session.ProjectName)
+	}
+
 	if session.GitBranch != "" {
 		meta += ui.MetaStyle.Render(" @ " + session.GitBranch)
+
+		switch claude.CachedSessionGitInfo(session).BranchState {
+		case claude.BranchGone:
+			meta += " " + ui.BranchGoneStyle.Render("gone")
+		case claude.BranchMerged:
+			meta += " " + ui.BranchMergedStyle.Render("merged")
+		}
 	}
 
 	meta += ui.MetaStyle.Render(fmt.Sprintf(" • %d messages • %s", session.MessageCount, formatTime(session.Modified)))
 
+	switch m.sortField {
+	case claude.SortCreated:
+		meta += ui.MetaStyle.Render(" • created " + formatTime(session.Created))
+	case claude.SortTokens:
+		meta += ui.MetaStyle.Render(" • " + formatCount(session.TokenUsage) + " tokens")
+	case claude.SortSize:
+		meta += ui.MetaStyle.Render(" • " + formatBytes(session.FileSize))
+	}
+
+	if session.IsProjectMissing() {
+		meta += " " + ui.MissingStyle.Render("Missing folder")
+	}
+
+	if session.Pinned {
+		meta += " " + ui.PinnedStyle.Render("★ Pinned")
+	}
+
 	if session.InTrash {
 		meta += " " + ui.TrashStyle.Render("In Bin")
 	}
@@ -461,38 +959,130 @@ func (m Model) renderSession(session *claude.Session, isSelected bool) string {
 	return builder.String()
 }
 
+func groupMarker(group *projectGroup) string {
+	if group.collapsed {
+		return "▸ "
+	}
+
+	return "▾ "
+}
+
+func (m Model) renderGroupHeader(group *projectGroup, isSelected bool) string {
+	var builder strings.Builder
+
+	cursor := "  "
+
+	if isSelected {
+		cursor = ui.CursorStyle.Render("▸ ")
+	}
+
+	builder.WriteString(cursor)
+
+	title := groupMarker(group) + group.name + fmt.Sprintf(" (%d)", group.sessionCount)
+
+	if isSelected {
+		builder.WriteString(ui.SelectedItemStyle.Render(truncate(title, m.contentWidth()-20)))
+	} else {
+		builder.WriteString(ui.ProjectStyle.Bold(true).Render(truncate(title, m.contentWidth()-20)))
+	}
+
+	builder.WriteString("\n")
+
+	if group.pinned {
+		builder.WriteString(ui.MetaStyle.Render("    Kept at the top • last active " + formatTime(group.lastActivity)))
+	} else {
+		builder.WriteString(ui.MetaStyle.Render(fmt.Sprintf("    %s • last active %s", group.path, formatTime(group.lastActivity))))
+	}
+
+	return builder.String()
+}
+
+func (m Model) renderGroupHeaderCompact(group *projectGroup, isSelected bool, maxWidth int) string {
+	cursor := "  "
+
+	if isSelected {
+		cursor = ui.CursorStyle.Render("▸ ")
+	}
+
+	title := truncate(groupMarker(group)+group.name+fmt.Sprintf(" (%d) • %s", group.sessionCount,
+		formatTime(group.lastActivity)), maxWidth-4)
+
+	if isSelected {
+		return cursor + ui.SelectedItemStyle.Render(title)
+	}
+
+	return cursor + ui.ProjectStyle.Bold(true).Render(title)
+}
+
+func (m Model) renderGroupPreview(group *projectGroup, width int) string {
+	lines := []string{
+		ui.PreviewHeaderStyle.Render(truncate(group.name, width-4)),
+		ui.MetaStyle.Render(truncate(group.path, width-4)),
+		ui.PreviewDividerStyle.Render(strings.Repeat("─", max(0, width-4))),
+		"",
+		ui.BaseStyle.Render(fmt.Sprintf("%d sessions • last active %s", group.sessionCount, formatTime(group.lastActivity))),
+		"",
+	}
+
+	deleteDescription := "move all to bin"
+
+	if m.tab == TabTrash {
+		deleteDescription = "delete all permanently"
+	}
+
+	actions := []struct{ key, description string }{
+		{m.keys.Collapse.Help().Key, "collapse or expand"},
+	}
+
+	if !group.pinned {
+		actions = append(actions, struct{ key, description string }{m.keys.ReassignAll.Help().Key, "reassign all"})
+	}
+
+	actions = append(actions, []struct{ key, description string }{
+		{m.keys.Delete.Help().Key, deleteDescription},
+		{m.keys.Export.Help().Key, "export all as markdown"},
+		{m.keys.ExportHTML.Help().Key, "export all as html"},
+	}...)
+
+	if m.tab == TabTrash {
+		actions = append(actions, struct{ key, description string }{m.keys.Restore.Help().Key, "restore all"})
+	}
+
+	for _, action := range actions {
+		lines = append(lines, "  "+ui.HelpKeyStyle.Render(fmt.Sprintf("%-8s", action.key))+ui.HelpStyle.Render(action.description))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderHelp() string {
 	var builder strings.Builder
 
 	builder.WriteString(ui.HeaderStyle.Render("Keyboard Shortcuts"))
 	builder.WriteString("\n\n")
 
-	helpItems := []struct{ key, description string }{
-		{"j / k", "Navigate up and down"},
-		{"h / l", "Switch between tabs"},
-		{"g g / G", "Jump to top or bottom"},
-		{"ctrl+u / ctrl+d", "Page up or down"},
-		{"/", "Filter sessions"},
-		{"s", "Search all sessions"},
-		{"n / N", "Next or previous match"},
-		{"p", "Toggle preview pane"},
-		{"tab", "Switch focus"},
-		{"d", "Move to Bin"},
-		{"u", "Restore from Bin"},
-		{"c", "Rename session"},
-		{"r", "Reassign folder"},
-		{"R", "Reassign all with folder"},
-		{"D", "Empty Bin"},
-		{"?", "Show help"},
-		{"q", "Quit"},
-	}
-
-	for _, item := range helpItems {
+	for _, entry := range m.keys.HelpEntries() {
 		builder.WriteString("  ")
-		builder.WriteString(ui.HelpKeyStyle.Render(fmt.Sprintf("%-16s", item.key)))
-		builder.WriteString(ui.HelpStyle.Render(item.description))
+		builder.WriteString(ui.HelpKeyStyle.Render(fmt.Sprintf("%-16s", entry.Key)))
+		builder.WriteString(ui.HelpStyle.Render(capitalise(entry.Description)))
 		builder.WriteString("\n")
 	}
 
 	return builder.String()
 }
+
+func (m Model) navigationKeys() string {
+	return helpKey(m.keys.Down) + "/" + helpKey(m.keys.Up)
+}
+
+func helpKey(binding key.Binding) string {
+	return binding.Help().Key
+}
+
+func capitalise(text string) string {
+	if text == "" {
+		return text
+	}
+
+	return strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,114 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const activeModifiedWindow = time.Minute
+
+var ErrFileChanged = errors.New("file changed while it was being updated")
+
+type SessionBusyError struct {
+	SessionID string
+	Reason    string
+}
+
+func (busyError *SessionBusyError) Error() string {
+	return fmt.Sprintf("session %s is in use (%s); try again once Claude Code has finished with it", busyError.SessionID, busyError.Reason)
+}
+
+type fileStamp struct {
+	exists   bool
+	modified time.Time
+	size     int64
+}
+
+func stampFile(filePath string) fileStamp {
+	fileInfo, statError := os.Stat(filePath)
+
+	if statError != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{exists: true, modified: fileInfo.ModTime(), size: fileInfo.Size()}
+}
+
+func (stamp fileStamp) matches(filePath string) bool {
+	return stampFile(filePath) == stamp
+}
+
+type activityChecker struct {
+	openFiles map[string]bool
+}
+
+func (checker *activityChecker) reason(sessionPath string) string {
+	fileInfo, statError := os.Stat(sessionPath)
+
+	if statError != nil {
+		return ""
+	}
+
+	if age := time.Since(fileInfo.ModTime()); age < activeModifiedWindow {
+		return fmt.Sprintf("modified %ds ago", int(age.Seconds()))
+	}
+
+	for _, lockPath := range []string{sessionPath + ".lock", filepath.Join(filepath.Dir(sessionPath), ".lock")} {
+		if _, lockError := os.Stat(lockPath); lockError == nil {
+			return "locked by " + filepath.Base(lockPath)
+		}
+	}
+
+	if checker.openFiles == nil {
+		checker.openFiles = openFilePaths()
+	}
+
+	if resolvedPath, resolveError := filepath.EvalSymlinks(sessionPath); resolveError == nil && checker.openFiles[resolvedPath] {
+		return "open in another process"
+	}
+
+	return ""
+}
+
+func SessionActivity(session *Session) string {
+	var checker activityChecker
+
+	return checker.reason(session.FullPath)
+}
+
+func openFilePaths() map[string]bool {
+	openFiles := map[string]bool{}
+	processEntries, readError := os.ReadDir("/proc")
+
+	if readError != nil {
+		return openFiles
+	}
+
+	ownProcess := fmt.Sprint(os.Getpid())
+
+	for _, processEntry := range processEntries {
+		name := processEntry.Name()
+
+		if name == ownProcess || name[0] < '0' || name[0] > '9' {
+			continue
+		}
+
+		descriptorDirectory := filepath.Join("/proc", name, "fd")
+		descriptorEntries, descriptorError := os.ReadDir(descriptorDirectory)
+
+		if descriptorError != nil {
+			continue
+		}
+
+		for _, descriptorEntry := range descriptorEntries {
+			if target, linkError := os.Readlink(filepath.Join(descriptorDirectory, descriptorEntry.Name())); linkError == nil {
+				openFiles[target] = true
+			}
+		}
+	}
+
+	return openFiles
+}
@@ -0,0 +1,318 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const autoTitleMaxLength = 60
+
+var (
+	wrapperTagPattern  = regexp.MustCompile(`(?s)<(command-message|command-args|local-command-stdout|local-command-stderr|system-reminder|user-prompt-submit-hook)>.*?</[a-z-]+>`)
+	anyTagPattern      = regexp.MustCompile(`</?[a-zA-Z][\w-]*(\s[^>]*)?>`)
+	codeFencePattern   = regexp.MustCompile("(?s)```.*?(```|$)")
+	locationPattern    = regexp.MustCompile(`^\S+\.\w+:\d+`)
+	errorLinePattern   = regexp.MustCompile(`^[\w.]*(Error|Exception)\b.*:`)
+	sentenceEndPattern = regexp.MustCompile(`[.?!](\s|$)`)
+	promptFillers      = []string{
+		"hey claude", "hi claude", "hello claude", "claude", "hey", "hi", "hello", "ok so", "okay so", "so",
+		"please", "can you please", "could you please", "can you", "could you", "would you", "will you",
+		"i want you to", "i need you to", "i'd like you to", "i would like you to", "help me", "let's", "lets",
+	}
+	defaultBranches = map[string]bool{"main": true, "master": true, "trunk": true, "develop": true, "HEAD": true}
+)
+
+type autoTitleLine struct {
+	Type        string `json:"type"`
+	IsSidechain bool   `json:"isSidechain"`
+	IsMeta      bool   `json:"isMeta"`
+	GitBranch   string `json:"gitBranch"`
+	Message     struct {
+		Content any `json:"content"`
+	} `json:"message"`
+}
+
+func GenerateTitle(session *Session) string {
+	prompts, touchedFiles, branch := scanTitleSources(session.FullPath)
+
+	if branch == "" {
+		branch = session.GitBranch
+	}
+
+	for _, prompt := range prompts {
+		if title := titleFromPrompt(prompt); title != "" {
+			return title
+		}
+	}
+
+	if title := titleFromBranch(branch); title != "" {
+		return title
+	}
+
+	if len(touchedFiles) > 0 {
+		return truncateTitle("Edit " + strings.Join(touchedFiles[:min(3, len(touchedFiles))], ", "))
+	}
+
+	return ""
+}
+
+func PlanAutoTitle(sessions []Session, overwrite bool) *Plan {
+	plan := &Plan{Description: "Generate session titles", ContinueOnError: true}
+
+	for sessionIndex := range sessions {
+		session := &sessions[sessionIndex]
+
+		if session.Summary != "" && !overwrite {
+			continue
+		}
+
+		title := GenerateTitle(session)
+
+		if title == "" || title == session.Summary {
+			continue
+		}
+
+		renamePlan := PlanRenameSession(session, title)
+
+		for operationIndex := range renamePlan.Operations {
+			renamePlan.Operations[operationIndex].Description = "Title " + session.SessionID + ": " + title
+		}
+
+		plan.Merge(renamePlan)
+	}
+
+	return plan
+}
+
+func scanTitleSources(filePath string) ([]string, []string, string) {
+	var prompts []string
+	var touchedFiles []string
+	var branch string
+
+	file, openError := os.Open(filePath)
+
+	if openError != nil {
+		return nil, nil, ""
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanBuffer := make([]byte, 0, 64*1024)
+
+	scanner.Buffer(scanBuffer, 10*1024*1024)
+
+	seenFiles := map[string]bool{}
+
+	for scanner.Scan() {
+		var line autoTitleLine
+
+		if unmarshalError := json.Unmarshal(scanner.Bytes(), &line); unmarshalError != nil || line.IsSidechain {
+			continue
+		}
+
+		if branch == "" && line.GitBranch != "" {
+			branch = line.GitBranch
+		}
+
+		switch line.Type {
+		case "user":
+			if line.IsMeta {
+				continue
+			}
+
+			switch content := line.Message.Content.(type) {
+			case string:
+				prompts = append(prompts, content)
+			case []any:
+				for _, block := range content {
+					if blockMap, ok := block.(map[string]any); ok && blockMap["type"] == "text" {
+						if text, ok := blockMap["text"].(string); ok {
+							prompts = append(prompts, text)
+						}
+					}
+				}
+			}
+		case "assistant":
+			blocks, ok := line.Message.Content.([]any)
+
+			if !ok {
+				continue
+			}
+
+			for _, block := range blocks {
+				blockMap, ok := block.(map[string]any)
+
+				if !ok || blockMap["type"] != "tool_use" {
+					continue
+				}
+
+				input, _ := blockMap["input"].(map[string]any)
+
+				for _, field := range []string{"file_path", "notebook_path"} {
+					if touchedPath, ok := input[field].(string); ok && touchedPath != "" {
+						name := filepath.Base(touchedPath)
+
+						if !seenFiles[name] {
+							seenFiles[name] = true
+							touchedFiles = append(touchedFiles, name)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return prompts, touchedFiles, branch
+}
+
+func titleFromPrompt(prompt string) string {
+	if strings.Contains(prompt, "<command-name>") || strings.HasPrefix(strings.TrimSpace(prompt), "Caveat:") ||
+		strings.HasPrefix(prompt, "[Request interrupted") {
+		return ""
+	}
+
+	prompt = wrapperTagPattern.ReplaceAllString(prompt, " ")
+	prompt = codeFencePattern.ReplaceAllString(prompt, "\n")
+	prompt = anyTagPattern.ReplaceAllString(prompt, " ")
+
+	var proseLines []string
+
+	for _, line := range strings.Split(prompt, "\n") {
+		if isBoilerplateLine(line) {
+			if len(proseLines) > 0 {
+				break
+			}
+
+			continue
+		}
+
+		proseLines = append(proseLines, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#>*- ")))
+	}
+
+	text := strings.Join(strings.Fields(strings.Join(proseLines, " ")), " ")
+
+	if location := sentenceEndPattern.FindStringIndex(text); location != nil {
+		text = text[:location[0]]
+	}
+
+	text = stripFillers(text)
+	text = strings.TrimRight(text, ".,:;!? ")
+
+	if len(strings.Fields(text)) < 2 || len(text) < 8 {
+		return ""
+	}
+
+	return truncateTitle(capitalise(text))
+}
+
+func isBoilerplateLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	if trimmed == "" {
+		return true
+	}
+
+	if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+		return true
+	}
+
+	for _, prefix := range []string{"at ", "File \"", "Traceback", "goroutine ", "panic:", "$ ", "> ", "//", "/*", "import ",
+		"func ", "def ", "class ", "const ", "let ", "var ", "return ", "}", "{", "[", "error[", "warning:"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+
+	if locationPattern.MatchString(trimmed) || errorLinePattern.MatchString(trimmed) {
+		return true
+	}
+
+	var symbolCount int
+	var letterCount int
+
+	for _, character := range trimmed {
+		switch {
+		case unicode.IsLetter(character):
+			letterCount += 1
+		case strings.ContainsRune("{}[]();=<>|&$\\`", character):
+			symbolCount += 1
+		}
+	}
+
+	return letterCount == 0 || symbolCount*4 > letterCount
+}
+
+func stripFillers(text string) string {
+	for {
+		stripped := false
+
+		for _, filler := range promptFillers {
+			if len(text) < len(filler) || !strings.EqualFold(text[:len(filler)], filler) {
+				continue
+			}
+
+			remainder := text[len(filler):]
+
+			if remainder != "" && !strings.ContainsRune(" ,!:", rune(remainder[0])) {
+				continue
+			}
+
+			text = strings.TrimLeft(remainder, " ,!:")
+			stripped = true
+
+			break
+		}
+
+		if !stripped {
+			return text
+		}
+	}
+}
+
+func titleFromBranch(branch string) string {
+	if branch == "" || defaultBranches[branch] {
+		return ""
+	}
+
+	words := strings.FieldsFunc(branch, func(character rune) bool {
+		return character == '-' || character == '_' || character == '/'
+	})
+
+	if len(words) < 2 {
+		return ""
+	}
+
+	return truncateTitle(capitalise(strings.Join(words, " ")))
+}
+
+func capitalise(text string) string {
+	runes := []rune(text)
+
+	if len(runes) == 0 {
+		return text
+	}
+
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+func truncateTitle(title string) string {
+	if len([]rune(title)) <= autoTitleMaxLength {
+		return title
+	}
+
+	runes := []rune(title)[:autoTitleMaxLength]
+
+	if spaceIndex := strings.LastIndex(string(runes), " "); spaceIndex > autoTitleMaxLength/2 {
+		return strings.TrimRight(string(runes)[:spaceIndex], ".,:;-— ") + "…"
+	}
+
+	return string(runes[:autoTitleMaxLength-1]) + "…"
+}
@@ -0,0 +1,276 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ProblemKind string
+
+const (
+	ProblemCorruptIndex   ProblemKind = "corrupt-index"
+	ProblemMissingFile    ProblemKind = "missing-file"
+	ProblemUnindexed      ProblemKind = "unindexed"
+	ProblemDuplicateID    ProblemKind = "duplicate-id"
+	ProblemLeftoverTemp   ProblemKind = "leftover-temp"
+	ProblemEmptyDirectory ProblemKind = "empty-directory"
+)
+
+type Problem struct {
+	Kind        ProblemKind
+	Path        string
+	SessionID   string
+	Explanation string
+	Fixable     bool
+}
+
+type DoctorReport struct {
+	DirectoriesScanned int
+	FilesScanned       int
+	Problems           []Problem
+}
+
+func (report *DoctorReport) FixableCount() int {
+	var count int
+
+	for _, problem := range report.Problems {
+		if problem.Fixable {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+func Diagnose() (*DoctorReport, error) {
+	report := &DoctorReport{}
+
+	if _, readError := os.ReadDir(ProjectsDir()); readError != nil {
+		return nil, readError
+	}
+
+	for _, root := range Roots() {
+		diagnoseRoot(report, root)
+	}
+
+	return report, nil
+}
+
+func diagnoseRoot(report *DoctorReport, root Root) {
+	sessionLocations := map[string][]string{}
+
+	for _, sessionDirectory := range []string{root.ProjectsDir(), root.TrashDir} {
+		directoryEntries, readError := os.ReadDir(sessionDirectory)
+
+		if readError != nil {
+			continue
+		}
+
+		for _, directoryEntry := range directoryEntries {
+			if !directoryEntry.IsDir() {
+				continue
+			}
+
+			diagnoseProjectDirectory(report, filepath.Join(sessionDirectory, directoryEntry.Name()), sessionLocations)
+		}
+	}
+
+	sessionIDs := make([]string, 0, len(sessionLocations))
+
+	for sessionID := range sessionLocations {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+
+	sort.Strings(sessionIDs)
+
+	for _, sessionID := range sessionIDs {
+		locations := sessionLocations[sessionID]
+
+		if len(locations) < 2 {
+			continue
+		}
+
+		report.Problems = append(report.Problems, Problem{
+			Kind:      ProblemDuplicateID,
+			Path:      strings.Join(locations, ", "),
+			SessionID: sessionID,
+			Explanation: fmt.Sprintf("The same session ID exists in %d project directories, so moves and restores may "+
+				"overwrite one copy; compare them and delete the stale one", len(locations)),
+		})
+	}
+}
+
+func isLeftoverTemp(directoryEntry os.DirEntry) bool {
+	name := directoryEntry.Name()
+
+	if directoryEntry.IsDir() || !strings.HasSuffix(name, ".tmp") {
+		return false
+	}
+
+	if !strings.HasPrefix(name, ".") {
+		return strings.HasSuffix(name, ".jsonl.tmp")
+	}
+
+	fileInfo, infoError := directoryEntry.Info()
+
+	return infoError == nil && time.Since(fileInfo.ModTime()) > activeModifiedWindow
+}
+
+func diagnoseProjectDirectory(report *DoctorReport, projectDirectory string, sessionLocations map[string][]string) {
+	directoryEntries, readError := os.ReadDir(projectDirectory)
+
+	if readError != nil {
+		return
+	}
+
+	report.DirectoriesScanned += 1
+
+	indexPath := filepath.Join(projectDirectory, "sessions-index.json")
+	sessionFiles := map[string]bool{}
+	hasIndex := false
+	hasContent := false
+
+	for _, directoryEntry := range directoryEntries {
+		name := directoryEntry.Name()
+		entryPath := filepath.Join(projectDirectory, name)
+
+		switch {
+		case name == "sessions-index.json":
+			hasIndex = true
+		case isLeftoverTemp(directoryEntry):
+			report.Problems = append(report.Problems, Problem{
+				Kind:        ProblemLeftoverTemp,
+				Path:        entryPath,
+				Explanation: "Temporary file left behind by an interrupted write; it is safe to remove",
+				Fixable:     true,
+			})
+		case !directoryEntry.IsDir() && strings.HasSuffix(name, ".jsonl"):
+			sessionID := strings.TrimSuffix(name, ".jsonl")
+			sessionFiles[sessionID] = true
+			sessionLocations[sessionID] = append(sessionLocations[sessionID], projectDirectory)
+			hasContent = true
+			report.FilesScanned += 1
+		default:
+			hasContent = true
+		}
+	}
+
+	var sessionIndex SessionIndex
+
+	if hasIndex {
+		fileData, fileError := os.ReadFile(indexPath)
+
+		if fileError != nil {
+			return
+		}
+
+		if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
+			report.Problems = append(report.Problems, Problem{
+				Kind:        ProblemCorruptIndex,
+				Path:        indexPath,
+				Explanation: "Index cannot be parsed (" + unmarshalError.Error() + "); it will be rebuilt from the JSONL files",
+				Fixable:     true,
+			})
+
+			return
+		}
+	}
+
+	if !hasContent && len(sessionIndex.Entries) == 0 {
+		report.Problems = append(report.Problems, Problem{
+			Kind:        ProblemEmptyDirectory,
+			Path:        projectDirectory,
+			Explanation: "Project directory holds no sessions and can be removed",
+			Fixable:     true,
+		})
+
+		return
+	}
+
+	if !hasIndex {
+		return
+	}
+
+	indexedSessions := map[string]bool{}
+
+	for _, entry := range sessionIndex.Entries {
+		indexedSessions[entry.SessionID] = true
+
+		if !sessionFiles[entry.SessionID] {
+			report.Problems = append(report.Problems, Problem{
+				Kind:        ProblemMissingFile,
+				Path:        indexPath,
+				SessionID:   entry.SessionID,
+				Explanation: "Index entry points at a JSONL file that no longer exists; the entry will be dropped",
+				Fixable:     true,
+			})
+		}
+	}
+
+	sessionIDs := make([]string, 0, len(sessionFiles))
+
+	for sessionID := range sessionFiles {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+
+	sort.Strings(sessionIDs)
+
+	for _, sessionID := range sessionIDs {
+		if !indexedSessions[sessionID] {
+			report.Problems = append(report.Problems, Problem{
+				Kind:        ProblemUnindexed,
+				Path:        filepath.Join(projectDirectory, sessionID+".jsonl"),
+				SessionID:   sessionID,
+				Explanation: "JSONL file is not listed in the index, so it is hidden from the session list; it will be added",
+				Fixable:     true,
+			})
+		}
+	}
+}
+
+func PlanDoctorFix(report *DoctorReport) *Plan {
+	plan := &Plan{Description: "Repair session storage", ContinueOnError: true}
+	rebuiltIndexes := map[string]bool{}
+
+	for _, problem := range report.Problems {
+		switch problem.Kind {
+		case ProblemLeftoverTemp:
+			plan.Add(Operation{
+				Description: "Remove leftover " + filepath.Base(problem.Path),
+				Steps:       []Step{{Kind: StepRemove, Path: problem.Path}},
+			})
+		case ProblemEmptyDirectory:
+			plan.Add(Operation{
+				Description: "Remove empty " + filepath.Base(problem.Path),
+				Steps:       []Step{{Kind: StepPruneDirectory, Path: problem.Path}},
+			})
+		case ProblemCorruptIndex, ProblemMissingFile, ProblemUnindexed:
+			indexPath := problem.Path
+
+			if problem.Kind == ProblemUnindexed {
+				indexPath = filepath.Join(filepath.Dir(problem.Path), "sessions-index.json")
+			}
+
+			if rebuiltIndexes[indexPath] {
+				continue
+			}
+
+			rebuiltIndexes[indexPath] = true
+
+			plan.Add(Operation{
+				Description: "Rebuild " + filepath.Base(filepath.Dir(indexPath)) + " index",
+				Steps: []Step{
+					{Kind: StepRebuildIndex, Path: indexPath},
+					{Kind: StepPruneDirectory, Path: filepath.Dir(indexPath)},
+				},
+			})
+		}
+	}
+
+	return plan
+}
@@ -0,0 +1,191 @@
+package claude
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type ExportFormat int
+
+const (
+	ExportMarkdown ExportFormat = iota
+	ExportHTML
+)
+
+func (exportFormat ExportFormat) String() string {
+	if exportFormat == ExportHTML {
+		return "HTML"
+	}
+
+	return "Markdown"
+}
+
+func (exportFormat ExportFormat) Extension() string {
+	if exportFormat == ExportHTML {
+		return ".html"
+	}
+
+	return ".md"
+}
+
+func ExportDir() string {
+	workingDirectory, workingDirectoryError := os.Getwd()
+
+	if workingDirectoryError != nil {
+		workingDirectory = "."
+	}
+
+	return filepath.Join(workingDirectory, "faustus-exports")
+}
+
+func ExportPath(session *Session, exportFormat ExportFormat) string {
+	return filepath.Join(ExportDir(), filepath.Base(ProjectDir(session)), session.SessionID+exportFormat.Extension())
+}
+
+func PlanExportSessions(sessions []Session, exportFormat ExportFormat) *Plan {
+	plan := &Plan{Description: "Export as " + exportFormat.String()}
+
+	for sessionIndex := range sessions {
+		session := sessions[sessionIndex]
+		exportPath := ExportPath(&session, exportFormat)
+
+		plan.Add(Operation{
+			Description: "Export " + session.SessionID,
+			SessionID:   session.SessionID,
+			Steps: []Step{
+				{Kind: StepCreateDirectory, Path: filepath.Dir(exportPath)},
+				{Kind: StepExport, Path: session.FullPath, Destination: exportPath, Value: exportFormat.String(), Entry: &session},
+			},
+		})
+	}
+
+	return plan
+}
+
+func exportSession(step *Step) error {
+	messages, readError := readSessionMessages(step.Path, false)
+
+	if readError != nil {
+		return readError
+	}
+
+	var document string
+
+	if step.Value == ExportHTML.String() {
+		document = renderHTMLExport(step.Entry, messages)
+	} else {
+		document = renderMarkdownExport(step.Entry, messages)
+	}
+
+	return writeFileAtomic(step.Destination, []byte(document), 0o644)
+}
+
+func exportTitle(session *Session) string {
+	if title := session.Title(); title != "" {
+		return strings.ReplaceAll(title, "\n", " ")
+	}
+
+	return session.SessionID
+}
+
+func exportRoleName(role string) string {
+	switch role {
+	case "user":
+		return "You"
+	case "assistant":
+		return "Claude"
+	case "tool":
+		return "Tool"
+	case "thinking":
+		return "Thinking"
+	}
+
+	return role
+}
+
+func exportMetadata(session *Session) [][2]string {
+	metadata := [][2]string{
+		{"Project", session.ProjectPath},
+		{"Session", session.SessionID},
+		{"Created", session.Created.Format("2006-01-02 15:04")},
+		{"Modified", session.Modified.Format("2006-01-02 15:04")},
+	}
+
+	if session.GitBranch != "" {
+		metadata = append(metadata, [2]string{"Branch", session.GitBranch})
+	}
+
+	return metadata
+}
+
+func renderMarkdownExport(session *Session, messages []PreviewMessage) string {
+	var builder strings.Builder
+
+	builder.WriteString("# " + exportTitle(session) + "\n\n")
+
+	for _, field := range exportMetadata(session) {
+		builder.WriteString(fmt.Sprintf("- **%s**: `%s`\n", field[0], field[1]))
+	}
+
+	if session.Note != "" {
+		builder.WriteString("\n> **Note**\n>\n> " + strings.ReplaceAll(session.Note, "\n", "\n> ") + "\n")
+	}
+
+	builder.WriteString("\n---\n")
+
+	for _, message := range messages {
+		switch message.Role {
+		case "tool":
+			builder.WriteString("\n> **Tool**: `" + message.Content + "`\n")
+		case "thinking":
+			builder.WriteString("\n<details><summary>Thinking</summary>\n\n" + message.Content + "\n\n</details>\n")
+		default:
+			builder.WriteString("\n### " + exportRoleName(message.Role) + "\n\n" + message.Content + "\n")
+		}
+	}
+
+	return builder.String()
+}
+
+func renderHTMLExport(session *Session, messages []PreviewMessage) string {
+	var builder strings.Builder
+
+	title := html.EscapeString(exportTitle(session))
+
+	builder.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	builder.WriteString("<title>" + title + "</title>\n")
+	builder.WriteString("<style>\n" +
+		"body { font-family: sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; }\n" +
+		".message { margin: 1rem 0; white-space: pre-wrap; }\n" +
+		".role { font-weight: bold; }\n" +
+		".user .role { color: #00A4FF; }\n" +
+		".assistant .role { color: #12C78F; }\n" +
+		".tool, .thinking { color: #858392; font-style: italic; }\n" +
+		".note { white-space: pre-wrap; border-left: 3px solid #E8FE96; margin: 1rem 0; padding-left: 1rem; }\n" +
+		"</style>\n</head>\n<body>\n")
+	builder.WriteString("<h1>" + title + "</h1>\n<dl>\n")
+
+	for _, field := range exportMetadata(session) {
+		builder.WriteString("<dt>" + field[0] + "</dt><dd><code>" + html.EscapeString(field[1]) + "</code></dd>\n")
+	}
+
+	builder.WriteString("</dl>\n")
+
+	if session.Note != "" {
+		builder.WriteString("<blockquote class=\"note\"><strong>Note</strong><br>" + html.EscapeString(session.Note) + "</blockquote>\n")
+	}
+
+	builder.WriteString("<hr>\n")
+
+	for _, message := range messages {
+		builder.WriteString(fmt.Sprintf("<div class=\"message %s\"><div class=\"role\">%s</div>%s</div>\n",
+			message.Role, exportRoleName(message.Role), html.EscapeString(message.Content)))
+	}
+
+	builder.WriteString("</body>\n</html>\n")
+
+	return builder.String()
+}
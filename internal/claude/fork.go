@@ -0,0 +1,98 @@
+package claude
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func newSessionID() string {
+	var identifier [16]byte
+
+	_, _ = rand.Read(identifier[:])
+
+	identifier[6] = identifier[6]&0x0f | 0x40
+	identifier[8] = identifier[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", identifier[0:4], identifier[4:6], identifier[6:8], identifier[8:10], identifier[10:16])
+}
+
+func PlanForkSession(session *Session) (*Plan, string) {
+	forkedID := newSessionID()
+	projectDirectory := ProjectDir(session)
+	forkedPath := filepath.Join(projectDirectory, forkedID+".jsonl")
+	forkedSession := *session
+	forkedSession.SessionID = forkedID
+	forkedSession.FullPath = forkedPath
+	forkedSession.Modified = time.Now()
+	forkedSession.Pinned = false
+	forkedSession.Tags = nil
+	forkedSession.Note = ""
+
+	if title := session.Title(); title != "" {
+		forkedSession.Summary = title + " (fork)"
+	}
+
+	indexPath := filepath.Join(projectDirectory, "sessions-index.json")
+	plan := &Plan{Description: "Fork session"}
+	operation := newSessionOperation("Fork "+session.SessionID+" as "+forkedID, session)
+	operation.Steps = []Step{{Kind: StepFork, Path: session.FullPath, Destination: forkedPath, SessionID: forkedID}}
+
+	if _, statError := os.Stat(indexPath); statError == nil {
+		operation.Steps = append(operation.Steps, Step{
+			Kind:      StepIndexAdd,
+			Path:      indexPath,
+			SessionID: forkedID,
+			Value:     session.ProjectPath,
+			Entry:     &forkedSession,
+		})
+	} else {
+		operation.Steps = append(operation.Steps, Step{Kind: StepRebuildIndex, Path: indexPath})
+	}
+
+	plan.Add(operation)
+
+	return plan, forkedID
+}
+
+func forkSession(step *Step) error {
+	if _, statError := os.Stat(step.Destination); statError == nil {
+		return fmt.Errorf("%s: %w", step.Destination, os.ErrExist)
+	}
+
+	fileData, readError := os.ReadFile(step.Path)
+
+	if readError != nil {
+		return readError
+	}
+
+	lines := strings.Split(string(fileData), "\n")
+
+	for lineIndex, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		var lineData map[string]any
+
+		if unmarshalError := json.Unmarshal([]byte(line), &lineData); unmarshalError != nil {
+			continue
+		}
+
+		if _, hasSessionID := lineData["sessionId"]; !hasSessionID {
+			continue
+		}
+
+		lineData["sessionId"] = step.SessionID
+
+		if forkedLine, marshalError := json.Marshal(lineData); marshalError == nil {
+			lines[lineIndex] = string(forkedLine)
+		}
+	}
+
+	return writeFileAtomic(step.Destination, []byte(strings.Join(lines, "\n")), 0o644)
+}
@@ -0,0 +1,375 @@
+package claude
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type BranchState int
+
+const (
+	BranchUnknown BranchState = iota
+	BranchLive
+	BranchMerged
+	BranchGone
+)
+
+func (branchState BranchState) String() string {
+	switch branchState {
+	case BranchLive:
+		return "live"
+	case BranchMerged:
+		return "merged"
+	case BranchGone:
+		return "gone"
+	}
+
+	return "unknown"
+}
+
+type GitInfo struct {
+	IsRepository  bool
+	Root          string
+	RemoteURL     string
+	DefaultBranch string
+	Branch        string
+	BranchState   BranchState
+}
+
+type GitCommit struct {
+	Hash    string
+	Subject string
+	Time    time.Time
+}
+
+type gitCommitCacheEntry struct {
+	modified time.Time
+	commits  []GitCommit
+}
+
+var (
+	gitInfoCache   = map[string]GitInfo{}
+	gitCommitCache = map[string]gitCommitCacheEntry{}
+	gitCacheMutex  sync.Mutex
+)
+
+func gitInfoKey(projectPath, branch string) string {
+	return projectPath + "\x00" + branch
+}
+
+func WarmGitCache(sessions []Session) {
+	for sessionIndex := range sessions {
+		SessionGitInfo(&sessions[sessionIndex])
+	}
+}
+
+func SessionGitInfo(session *Session) GitInfo {
+	return LoadGitInfo(session.ResolvedPath, session.GitBranch)
+}
+
+func CachedSessionGitInfo(session *Session) GitInfo {
+	gitCacheMutex.Lock()
+
+	cached, isCached := gitInfoCache[gitInfoKey(session.ResolvedPath, session.GitBranch)]
+
+	gitCacheMutex.Unlock()
+
+	if !isCached {
+		return GitInfo{Branch: session.GitBranch}
+	}
+
+	return cached
+}
+
+func ResetGitCache() {
+	gitCacheMutex.Lock()
+
+	gitInfoCache = map[string]GitInfo{}
+	gitCommitCache = map[string]gitCommitCacheEntry{}
+
+	gitCacheMutex.Unlock()
+}
+
+func LoadGitInfo(projectPath, branch string) GitInfo {
+	if projectPath == "" {
+		return GitInfo{}
+	}
+
+	cacheKey := gitInfoKey(projectPath, branch)
+
+	gitCacheMutex.Lock()
+
+	cached, isCached := gitInfoCache[cacheKey]
+
+	gitCacheMutex.Unlock()
+
+	if isCached {
+		return cached
+	}
+
+	gitInfo := inspectGitRepository(projectPath, branch)
+
+	gitCacheMutex.Lock()
+
+	gitInfoCache[cacheKey] = gitInfo
+
+	gitCacheMutex.Unlock()
+
+	return gitInfo
+}
+
+func inspectGitRepository(projectPath, branch string) GitInfo {
+	if _, statError := os.Stat(projectPath); statError != nil {
+		return GitInfo{Branch: branch}
+	}
+
+	repositoryRoot := findGitRoot(projectPath)
+
+	if repositoryRoot == "" {
+		return GitInfo{Branch: branch}
+	}
+
+	gitDirectory := resolveGitDirectory(repositoryRoot)
+	commonDirectory := resolveCommonDirectory(gitDirectory)
+	gitInfo := GitInfo{
+		IsRepository:  true,
+		Root:          repositoryRoot,
+		RemoteURL:     readRemoteURL(commonDirectory),
+		DefaultBranch: readDefaultBranch(commonDirectory),
+		Branch:        branch,
+	}
+
+	if branch == "" || branch == "HEAD" {
+		return gitInfo
+	}
+
+	switch {
+	case !referenceExists(commonDirectory, "refs/heads/"+branch):
+		gitInfo.BranchState = BranchGone
+	case gitInfo.DefaultBranch != "" && branch != gitInfo.DefaultBranch &&
+		isAncestor(repositoryRoot, "refs/heads/"+branch, "refs/heads/"+gitInfo.DefaultBranch):
+		gitInfo.BranchState = BranchMerged
+	default:
+		gitInfo.BranchState = BranchLive
+	}
+
+	return gitInfo
+}
+
+func resolveGitDirectory(repositoryRoot string) string {
+	dotGit := filepath.Join(repositoryRoot, ".git")
+	fileInfo, statError := os.Stat(dotGit)
+
+	if statError != nil || fileInfo.IsDir() {
+		return dotGit
+	}
+
+	fileData, readError := os.ReadFile(dotGit)
+
+	if readError != nil {
+		return dotGit
+	}
+
+	gitDirectory, hasPrefix := strings.CutPrefix(strings.TrimSpace(string(fileData)), "gitdir:")
+
+	if !hasPrefix {
+		return dotGit
+	}
+
+	gitDirectory = strings.TrimSpace(gitDirectory)
+
+	if !filepath.IsAbs(gitDirectory) {
+		gitDirectory = filepath.Join(repositoryRoot, gitDirectory)
+	}
+
+	return gitDirectory
+}
+
+func resolveCommonDirectory(gitDirectory string) string {
+	fileData, readError := os.ReadFile(filepath.Join(gitDirectory, "commondir"))
+
+	if readError != nil {
+		return gitDirectory
+	}
+
+	commonDirectory := strings.TrimSpace(string(fileData))
+
+	if !filepath.IsAbs(commonDirectory) {
+		commonDirectory = filepath.Join(gitDirectory, commonDirectory)
+	}
+
+	return filepath.Clean(commonDirectory)
+}
+
+func readRemoteURL(commonDirectory string) string {
+	file, openError := os.Open(filepath.Join(commonDirectory, "config"))
+
+	if openError != nil {
+		return ""
+	}
+
+	defer func() { _ = file.Close() }()
+
+	var currentRemote, firstURL, originURL string
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			currentRemote = ""
+
+			if remoteName, isRemote := strings.CutPrefix(line, "[remote \""); isRemote {
+				currentRemote = strings.TrimSuffix(remoteName, "\"]")
+			}
+
+			continue
+		}
+
+		if currentRemote == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(line, "=")
+
+		if !hasValue || strings.TrimSpace(name) != "url" {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+
+		if firstURL == "" {
+			firstURL = value
+		}
+
+		if currentRemote == "origin" {
+			originURL = value
+		}
+	}
+
+	if originURL != "" {
+		return originURL
+	}
+
+	return firstURL
+}
+
+func readDefaultBranch(commonDirectory string) string {
+	fileData, readError := os.ReadFile(filepath.Join(commonDirectory, "refs", "remotes", "origin", "HEAD"))
+
+	if readError == nil {
+		if target, isSymbolic := strings.CutPrefix(strings.TrimSpace(string(fileData)), "ref: refs/remotes/origin/"); isSymbolic {
+			if referenceExists(commonDirectory, "refs/heads/"+target) {
+				return target
+			}
+		}
+	}
+
+	for _, candidate := range []string{"main", "master", "trunk"} {
+		if referenceExists(commonDirectory, "refs/heads/"+candidate) {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+func referenceExists(commonDirectory, reference string) bool {
+	if _, statError := os.Stat(filepath.Join(commonDirectory, filepath.FromSlash(reference))); statError == nil {
+		return true
+	}
+
+	file, openError := os.Open(filepath.Join(commonDirectory, "packed-refs"))
+
+	if openError != nil {
+		return false
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+
+		if _, packedReference, hasReference := strings.Cut(line, " "); hasReference && packedReference == reference {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isAncestor(repositoryRoot, ancestor, descendant string) bool {
+	command := exec.Command("git", "-C", repositoryRoot, "merge-base", "--is-ancestor", ancestor, descendant)
+	command.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+
+	return command.Run() == nil
+}
+
+func SessionCommits(session *Session) []GitCommit {
+	gitInfo := SessionGitInfo(session)
+
+	if !gitInfo.IsRepository || session.Created.IsZero() {
+		return nil
+	}
+
+	gitCacheMutex.Lock()
+
+	cached, isCached := gitCommitCache[session.SessionID]
+
+	gitCacheMutex.Unlock()
+
+	if isCached && cached.modified.Equal(session.Modified) {
+		return cached.commits
+	}
+
+	arguments := []string{
+		"-C", gitInfo.Root, "log", "--max-count=50", "--format=%H%x1f%s%x1f%cI",
+		"--since=" + session.Created.Format(time.RFC3339),
+		"--until=" + session.Modified.Format(time.RFC3339),
+	}
+
+	if gitInfo.BranchState == BranchLive || gitInfo.BranchState == BranchMerged {
+		arguments = append(arguments, "refs/heads/"+gitInfo.Branch)
+	} else {
+		arguments = append(arguments, "--all")
+	}
+
+	command := exec.Command("git", arguments...)
+	command.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	output, runError := command.Output()
+
+	var commits []GitCommit
+
+	if runError == nil {
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			fields := strings.Split(line, "\x1f")
+
+			if len(fields) != 3 {
+				continue
+			}
+
+			commitTime, _ := time.Parse(time.RFC3339, fields[2])
+			commits = append(commits, GitCommit{Hash: fields[0], Subject: fields[1], Time: commitTime})
+		}
+	}
+
+	gitCacheMutex.Lock()
+
+	gitCommitCache[session.SessionID] = gitCommitCacheEntry{modified: session.Modified, commits: commits}
+
+	gitCacheMutex.Unlock()
+
+	return commits
+}
@@ -0,0 +1,143 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+func PlanRebuildIndex(projectDirectory string) *Plan {
+	plan := &Plan{Description: "Rebuild " + filepath.Base(projectDirectory) + " index"}
+
+	plan.Add(rebuildIndexOperation(projectDirectory))
+
+	return plan
+}
+
+func PlanRebuildAllIndexes() (*Plan, error) {
+	plan := &Plan{Description: "Rebuild every session index", ContinueOnError: true}
+
+	if _, readError := os.ReadDir(ProjectsDir()); readError != nil {
+		return nil, readError
+	}
+
+	for _, sessionDirectory := range sessionDirectories() {
+		directoryEntries, readError := os.ReadDir(sessionDirectory)
+
+		if readError != nil {
+			continue
+		}
+
+		for _, directoryEntry := range directoryEntries {
+			projectDirectory := filepath.Join(sessionDirectory, directoryEntry.Name())
+
+			if directoryEntry.IsDir() && CountSessionFiles(projectDirectory) > 0 {
+				plan.Add(rebuildIndexOperation(projectDirectory))
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func rebuildIndexOperation(projectDirectory string) Operation {
+	return Operation{
+		Description: "Rebuild " + filepath.Base(projectDirectory) + " index",
+		Steps:       []Step{{Kind: StepRebuildIndex, Path: filepath.Join(projectDirectory, "sessions-index.json")}},
+	}
+}
+
+func indexContainsSession(indexPath, sessionID string) bool {
+	fileData, readError := os.ReadFile(indexPath)
+
+	if readError != nil {
+		return false
+	}
+
+	var sessionIndex SessionIndex
+
+	if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
+		return false
+	}
+
+	for _, entry := range sessionIndex.Entries {
+		if entry.SessionID == sessionID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func rebuildIndex(indexPath string) error {
+	projectDirectory := filepath.Dir(indexPath)
+	parsedSessions := loadSessionsFromJsonlFiles(projectDirectory, filepath.Base(projectDirectory), false)
+
+	if fileData, readError := os.ReadFile(indexPath); readError == nil && !json.Valid(fileData) {
+		if removeError := os.Remove(indexPath); removeError != nil {
+			return removeError
+		}
+	}
+
+	originalPath := ""
+
+	for _, session := range parsedSessions {
+		if session.ProjectPath != "" {
+			originalPath = session.ProjectPath
+
+			break
+		}
+	}
+
+	return updateIndex(indexPath, originalPath, true, func(sessionIndex *SessionIndex) bool {
+		existingEntries := map[string]Session{}
+		rebuiltEntries := make([]Session, 0, len(parsedSessions))
+		parsedIDs := map[string]bool{}
+
+		for _, entry := range sessionIndex.Entries {
+			existingEntries[entry.SessionID] = entry
+		}
+
+		for _, session := range parsedSessions {
+			parsedIDs[session.SessionID] = true
+
+			if existing, isIndexed := existingEntries[session.SessionID]; isIndexed {
+				existing.FullPath = session.FullPath
+				existing.Modified = session.Modified
+				existing.MessageCount = session.MessageCount
+
+				if existing.Summary == "" {
+					existing.Summary = session.Summary
+				}
+
+				if existing.FirstPrompt == "" {
+					existing.FirstPrompt = session.FirstPrompt
+				}
+
+				rebuiltEntries = append(rebuiltEntries, existing)
+
+				continue
+			}
+
+			rebuiltEntries = append(rebuiltEntries, session)
+		}
+
+		for _, entry := range sessionIndex.Entries {
+			if parsedIDs[entry.SessionID] {
+				continue
+			}
+
+			if _, statError := os.Stat(filepath.Join(projectDirectory, entry.SessionID+".jsonl")); statError == nil {
+				rebuiltEntries = append(rebuiltEntries, entry)
+			}
+		}
+
+		sessionIndex.Entries = rebuiltEntries
+
+		if sessionIndex.OriginalPath == "" {
+			sessionIndex.OriginalPath = originalPath
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,296 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type SessionMetadata struct {
+	Tags   []string `json:"tags,omitempty"`
+	Pinned bool     `json:"pinned,omitempty"`
+	Note   string   `json:"note,omitempty"`
+}
+
+type Metadata struct {
+	Version  int                        `json:"version"`
+	Sessions map[string]SessionMetadata `json:"sessions"`
+}
+
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+func MetadataPath() string {
+	return filepath.Join(ClaudeDir(), "faustus", "metadata.json")
+}
+
+func metadataKey(rootLabel, sessionID string) string {
+	return rootLabel + "/" + sessionID
+}
+
+func LoadMetadata() (*Metadata, error) {
+	metadata := &Metadata{Version: 2, Sessions: map[string]SessionMetadata{}}
+	fileData, readError := os.ReadFile(MetadataPath())
+
+	if os.IsNotExist(readError) {
+		return metadata, nil
+	}
+
+	if readError != nil {
+		return nil, readError
+	}
+
+	if unmarshalError := json.Unmarshal(fileData, metadata); unmarshalError != nil {
+		return nil, unmarshalError
+	}
+
+	if metadata.Sessions == nil {
+		metadata.Sessions = map[string]SessionMetadata{}
+	}
+
+	if metadata.Version < 2 {
+		migrateMetadataKeys(metadata)
+	}
+
+	return metadata, nil
+}
+
+func migrateMetadataKeys(metadata *Metadata) {
+	migratedSessions := map[string]SessionMetadata{}
+
+	for sessionID, sessionMetadata := range metadata.Sessions {
+		isPlaced := false
+
+		for _, root := range Roots() {
+			if rootHasSession(root, sessionID) {
+				migratedSessions[metadataKey(root.Label, sessionID)] = sessionMetadata
+				isPlaced = true
+			}
+		}
+
+		if !isPlaced {
+			migratedSessions[metadataKey(PrimaryRoot().Label, sessionID)] = sessionMetadata
+		}
+	}
+
+	metadata.Version = 2
+	metadata.Sessions = migratedSessions
+}
+
+func rootHasSession(root Root, sessionID string) bool {
+	for _, inTrash := range []bool{false, true} {
+		matches, _ := filepath.Glob(filepath.Join(root.sessionDirectory(inTrash), "*", sessionID+".jsonl"))
+
+		if len(matches) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func updateMetadata(rootLabel, sessionID string, update func(*SessionMetadata)) error {
+	metadataPath := MetadataPath()
+
+	if mkdirError := os.MkdirAll(filepath.Dir(metadataPath), 0o755); mkdirError != nil {
+		return mkdirError
+	}
+
+	unlock, lockError := lockDirectory(filepath.Dir(metadataPath))
+
+	if lockError != nil {
+		return lockError
+	}
+
+	defer unlock()
+
+	metadata, loadError := LoadMetadata()
+
+	if loadError != nil {
+		return loadError
+	}
+
+	sessionKey := metadataKey(rootLabel, sessionID)
+	sessionMetadata := metadata.Sessions[sessionKey]
+
+	update(&sessionMetadata)
+
+	if sessionMetadata.isEmpty() {
+		delete(metadata.Sessions, sessionKey)
+	} else {
+		metadata.Sessions[sessionKey] = sessionMetadata
+	}
+
+	jsonData, marshalError := json.MarshalIndent(metadata, "", "  ")
+
+	if marshalError != nil {
+		return marshalError
+	}
+
+	return writeFileAtomic(metadataPath, jsonData, 0o644)
+}
+
+func (sessionMetadata *SessionMetadata) isEmpty() bool {
+	return len(sessionMetadata.Tags) == 0 && !sessionMetadata.Pinned && sessionMetadata.Note == ""
+}
+
+func applyMetadata(sessions []Session) {
+	metadata, loadError := LoadMetadata()
+
+	if loadError != nil {
+		return
+	}
+
+	for sessionIndex := range sessions {
+		sessionMetadata := metadata.Sessions[metadataKey(sessions[sessionIndex].Root, sessions[sessionIndex].SessionID)]
+		sessions[sessionIndex].Tags = sessionMetadata.Tags
+		sessions[sessionIndex].Pinned = sessionMetadata.Pinned
+		sessions[sessionIndex].Note = sessionMetadata.Note
+	}
+}
+
+func PlanSetTags(session *Session, tags []string) *Plan {
+	return planMetadata("Tag session", session, Step{Kind: StepSetTags, Value: strings.Join(tags, " ")})
+}
+
+func PlanSetPinned(session *Session, pinned bool) *Plan {
+	if pinned {
+		return planMetadata("Pin session", session, Step{Kind: StepSetPinned, Value: "pinned"})
+	}
+
+	return planMetadata("Unpin session", session, Step{Kind: StepSetPinned})
+}
+
+func PlanSetNote(session *Session, note string) *Plan {
+	return planMetadata("Edit note", session, Step{Kind: StepSetNote, Value: strings.TrimSpace(note)})
+}
+
+func planMetadata(description string, session *Session, step Step) *Plan {
+	plan := &Plan{Description: description}
+	step.Path = MetadataPath()
+	step.SessionID = session.SessionID
+	step.Root = session.Root
+
+	plan.Add(Operation{
+		Description: description + " " + session.SessionID,
+		SessionID:   session.SessionID,
+		Steps:       []Step{step},
+	})
+
+	return plan
+}
+
+func metadataTransferSteps(session *Session, destinationRoot string) []Step {
+	var steps []Step
+
+	if len(session.Tags) > 0 {
+		steps = append(steps, Step{Kind: StepSetTags, Value: strings.Join(session.Tags, " ")})
+	}
+
+	if session.Pinned {
+		steps = append(steps, Step{Kind: StepSetPinned, Value: "pinned"})
+	}
+
+	if session.Note != "" {
+		steps = append(steps, Step{Kind: StepSetNote, Value: session.Note})
+	}
+
+	for stepIndex := range steps {
+		steps[stepIndex].Path = MetadataPath()
+		steps[stepIndex].SessionID = session.SessionID
+		steps[stepIndex].Root = destinationRoot
+	}
+
+	return steps
+}
+
+func removeMetadataStep(rootLabel, sessionID string) Step {
+	return Step{Kind: StepRemoveMetadata, Path: MetadataPath(), SessionID: sessionID, Root: rootLabel}
+}
+
+func applyMetadataStep(step *Step) error {
+	return updateMetadata(step.Root, step.SessionID, func(sessionMetadata *SessionMetadata) {
+		switch step.Kind {
+		case StepSetTags:
+			sessionMetadata.Tags = strings.Fields(step.Value)
+		case StepSetPinned:
+			sessionMetadata.Pinned = step.Value != ""
+		case StepSetNote:
+			sessionMetadata.Note = step.Value
+		case StepRemoveMetadata:
+			*sessionMetadata = SessionMetadata{}
+		}
+	})
+}
+
+func pinnedSessionKeys() map[string]bool {
+	pinnedKeys := map[string]bool{}
+	metadata, loadError := LoadMetadata()
+
+	if loadError != nil {
+		return pinnedKeys
+	}
+
+	for sessionKey, sessionMetadata := range metadata.Sessions {
+		if sessionMetadata.Pinned {
+			pinnedKeys[sessionKey] = true
+		}
+	}
+
+	return pinnedKeys
+}
+
+func ParseTags(input string) []string {
+	var tags []string
+
+	fields := strings.FieldsFunc(strings.ToLower(input), func(character rune) bool {
+		return character == ',' || character == ' ' || character == '\t'
+	})
+
+	for _, field := range fields {
+		tag := strings.TrimLeft(field, "#")
+
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func CountTags(sessions []Session) ([]TagCount, int) {
+	counts := map[string]int{}
+
+	var untaggedCount int
+
+	for _, session := range sessions {
+		if len(session.Tags) == 0 {
+			untaggedCount += 1
+		}
+
+		for _, tag := range session.Tags {
+			counts[tag] += 1
+		}
+	}
+
+	tagCounts := make([]TagCount, 0, len(counts))
+
+	for tag, count := range counts {
+		tagCounts = append(tagCounts, TagCount{Tag: tag, Count: count})
+	}
+
+	sort.Slice(tagCounts, func(first, second int) bool {
+		if tagCounts[first].Count != tagCounts[second].Count {
+			return tagCounts[first].Count > tagCounts[second].Count
+		}
+
+		return tagCounts[first].Tag < tagCounts[second].Tag
+	})
+
+	return tagCounts, untaggedCount
+}
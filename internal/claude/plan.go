@@ -0,0 +1,320 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type StepKind string
+
+const (
+	StepCreateDirectory StepKind = "mkdir"
+	StepMove            StepKind = "move"
+	StepCopy            StepKind = "copy"
+	StepRemove          StepKind = "remove"
+	StepPruneDirectory  StepKind = "prune"
+	StepRewriteCwd      StepKind = "rewrite-cwd"
+	StepIndexAdd        StepKind = "index-add"
+	StepIndexRemove     StepKind = "index-remove"
+	StepIndexSummary    StepKind = "index-summary"
+	StepRebuildIndex    StepKind = "rebuild-index"
+	StepSetTags         StepKind = "set-tags"
+	StepSetPinned       StepKind = "set-pinned"
+	StepSetNote         StepKind = "set-note"
+	StepRemoveMetadata  StepKind = "remove-metadata"
+	StepExport          StepKind = "export"
+	StepFork            StepKind = "fork"
+)
+
+type Step struct {
+	Kind        StepKind `json:"kind"`
+	Path        string   `json:"path"`
+	Destination string   `json:"destination,omitempty"`
+	SessionID   string   `json:"sessionId,omitempty"`
+	Root        string   `json:"root,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Entry       *Session `json:"entry,omitempty"`
+	Lines       int      `json:"lines,omitempty"`
+	Optional    bool     `json:"optional,omitempty"`
+}
+
+type Operation struct {
+	Description     string    `json:"description"`
+	SessionID       string    `json:"sessionId,omitempty"`
+	SessionPath     string    `json:"sessionPath,omitempty"`
+	SessionModified time.Time `json:"sessionModified,omitzero"`
+	SessionSize     int64     `json:"sessionSize,omitempty"`
+	Steps           []Step    `json:"steps"`
+}
+
+type Plan struct {
+	Description     string      `json:"description"`
+	Operations      []Operation `json:"operations"`
+	ContinueOnError bool        `json:"continueOnError,omitempty"`
+}
+
+func (step Step) String() string {
+	switch step.Kind {
+	case StepCreateDirectory:
+		return "mkdir   " + step.Path
+	case StepMove:
+		return "move    " + step.Path + " → " + step.Destination
+	case StepCopy:
+		return "copy    " + step.Path + " → " + step.Destination
+	case StepRemove:
+		return "remove  " + step.Path
+	case StepPruneDirectory:
+		return "prune   " + step.Path + " (if empty)"
+	case StepRewriteCwd:
+		return fmt.Sprintf("rewrite %s (%d lines, cwd → %s)", step.Path, step.Lines, step.Value)
+	case StepIndexAdd:
+		return "index + " + step.SessionID + " in " + step.Path
+	case StepIndexRemove:
+		return "index - " + step.SessionID + " from " + step.Path
+	case StepIndexSummary:
+		return "index ~ " + step.SessionID + " summary → " + step.Value + " in " + step.Path
+	case StepRebuildIndex:
+		return "rebuild " + step.Path + " from JSONL files"
+	case StepSetTags:
+		if step.Value == "" {
+			return "tags    " + step.SessionID + " cleared in " + step.Path
+		}
+
+		return "tags    " + step.SessionID + " → " + step.Value + " in " + step.Path
+	case StepSetPinned:
+		if step.Value == "" {
+			return "unpin   " + step.SessionID + " in " + step.Path
+		}
+
+		return "pin     " + step.SessionID + " in " + step.Path
+	case StepSetNote:
+		if step.Value == "" {
+			return "note    " + step.SessionID + " removed in " + step.Path
+		}
+
+		return fmt.Sprintf("note    %s → %q in %s", step.SessionID, step.Value, step.Path)
+	case StepRemoveMetadata:
+		return "forget  " + step.SessionID + " tags, pin and note in " + step.Path
+	case StepExport:
+		return "export  " + step.Path + " → " + step.Destination
+	case StepFork:
+		return "fork    " + step.Path + " → " + step.Destination
+	}
+
+	return string(step.Kind) + " " + step.Path
+}
+
+func newSessionOperation(description string, session *Session) Operation {
+	operation := Operation{
+		Description: description,
+		SessionID:   session.SessionID,
+		SessionPath: session.FullPath,
+	}
+
+	if fileInfo, statError := os.Stat(session.FullPath); statError == nil {
+		operation.SessionModified = fileInfo.ModTime()
+		operation.SessionSize = fileInfo.Size()
+	}
+
+	return operation
+}
+
+func (plan *Plan) Add(operation Operation) {
+	if len(operation.Steps) > 0 {
+		plan.Operations = append(plan.Operations, operation)
+	}
+}
+
+func (plan *Plan) Merge(other *Plan) {
+	plan.Operations = append(plan.Operations, other.Operations...)
+}
+
+func (plan *Plan) StepCount() int {
+	var count int
+
+	for _, operation := range plan.Operations {
+		count += len(operation.Steps)
+	}
+
+	return count
+}
+
+func (plan *Plan) IsEmpty() bool {
+	return len(plan.Operations) == 0
+}
+
+func (plan *Plan) Lines() []string {
+	var lines []string
+
+	for _, operation := range plan.Operations {
+		lines = append(lines, operation.Description)
+
+		for _, step := range operation.Steps {
+			lines = append(lines, "  "+step.String())
+		}
+	}
+
+	return lines
+}
+
+func (plan *Plan) Apply() (int, error) {
+	var appliedCount int
+	var failures []error
+	var checker activityChecker
+
+	for _, operation := range plan.Operations {
+		applyError := checkOperation(&operation, &checker)
+
+		if applyError == nil {
+			applyError = applyOperation(&operation)
+		}
+
+		if applyError != nil {
+			if !plan.ContinueOnError {
+				return appliedCount, applyError
+			}
+
+			failures = append(failures, fmt.Errorf("%s: %w", operation.Description, applyError))
+
+			continue
+		}
+
+		appliedCount += 1
+	}
+
+	return appliedCount, errors.Join(failures...)
+}
+
+func (plan *Plan) syncSession(session *Session) {
+	for _, operation := range plan.Operations {
+		for _, step := range operation.Steps {
+			if step.Kind == StepIndexAdd && step.SessionID == session.SessionID && step.Entry != nil {
+				*session = *step.Entry
+			}
+		}
+	}
+}
+
+func checkOperation(operation *Operation, checker *activityChecker) error {
+	if operation.SessionPath == "" {
+		return nil
+	}
+
+	if reason := checker.reason(operation.SessionPath); reason != "" {
+		return &SessionBusyError{SessionID: operation.SessionID, Reason: reason}
+	}
+
+	if operation.SessionModified.IsZero() {
+		return nil
+	}
+
+	plannedStamp := fileStamp{exists: true, modified: operation.SessionModified, size: operation.SessionSize}
+
+	if !plannedStamp.matches(operation.SessionPath) {
+		return fmt.Errorf("%s: %w since the plan was made", operation.SessionPath, ErrFileChanged)
+	}
+
+	return nil
+}
+
+func applyStep(step *Step) error {
+	switch step.Kind {
+	case StepCreateDirectory:
+		return os.MkdirAll(step.Path, 0o755)
+	case StepMove:
+		if _, statError := os.Stat(step.Path); os.IsNotExist(statError) && step.Optional {
+			return nil
+		}
+
+		return movePath(step.Path, step.Destination)
+	case StepCopy:
+		if _, statError := os.Stat(step.Path); os.IsNotExist(statError) && step.Optional {
+			return nil
+		}
+
+		if _, statError := os.Stat(step.Destination); statError == nil {
+			return fmt.Errorf("%s: %w", step.Destination, os.ErrExist)
+		}
+
+		return copyPath(step.Path, step.Destination)
+	case StepRewriteCwd:
+		return updateJsonlProjectPath(step.Path, step.Value)
+	case StepIndexAdd:
+		return addToIndexWithPath(step.Path, step.Entry, step.Value)
+	case StepIndexRemove:
+		return removeFromIndex(filepath.Dir(step.Path), step.SessionID)
+	case StepIndexSummary:
+		return updateIndexSummary(step.Path, step.SessionID, step.Value)
+	case StepRebuildIndex:
+		return rebuildIndex(step.Path)
+	case StepSetTags, StepSetPinned, StepSetNote, StepRemoveMetadata:
+		return applyMetadataStep(step)
+	case StepExport:
+		return exportSession(step)
+	case StepFork:
+		return forkSession(step)
+	}
+
+	return fmt.Errorf("unknown plan step %q", step.Kind)
+}
+
+func isPrunable(projectDirectory string) bool {
+	entries, readError := os.ReadDir(projectDirectory)
+
+	if readError != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.Name() != "sessions-index.json" {
+			return false
+		}
+
+		fileData, fileError := os.ReadFile(filepath.Join(projectDirectory, entry.Name()))
+
+		if fileError != nil {
+			return false
+		}
+
+		var sessionIndex SessionIndex
+
+		if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil || len(sessionIndex.Entries) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func countCwdLines(filePath string) int {
+	fileData, readError := os.ReadFile(filePath)
+
+	if readError != nil {
+		return 0
+	}
+
+	var count int
+
+	for _, line := range strings.Split(string(fileData), "\n") {
+		if !strings.Contains(line, "\"cwd\"") {
+			continue
+		}
+
+		var lineData map[string]any
+
+		if unmarshalError := json.Unmarshal([]byte(line), &lineData); unmarshalError != nil {
+			continue
+		}
+
+		if _, hasCwd := lineData["cwd"]; hasCwd {
+			count += 1
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package claude
+
+func lockDirectory(directory string) (func(), error) {
+	return func() {}, nil
+}
+
+func processIsRunning(processID int) bool {
+	return false
+}
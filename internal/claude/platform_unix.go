@@ -0,0 +1,37 @@
+//go:build unix
+
+package claude
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockDirectory(directory string) (func(), error) {
+	directoryFile, openError := os.Open(directory)
+
+	if openError != nil {
+		return func() {}, openError
+	}
+
+	if lockError := syscall.Flock(int(directoryFile.Fd()), syscall.LOCK_EX); lockError != nil {
+		_ = directoryFile.Close()
+
+		return func() {}, lockError
+	}
+
+	return func() {
+		_ = syscall.Flock(int(directoryFile.Fd()), syscall.LOCK_UN)
+		_ = directoryFile.Close()
+	}, nil
+}
+
+func processIsRunning(processID int) bool {
+	if processID <= 0 {
+		return false
+	}
+
+	signalError := syscall.Kill(processID, 0)
+
+	return signalError == nil || signalError == syscall.EPERM
+}
@@ -3,15 +3,24 @@ package claude
 import (
 	"bufio"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 )
 
+var previewTruncateLength = 500
+
 type RawMessage struct {
 	Type    string          `json:"type"`
+	UUID    string          `json:"uuid"`
 	Message json.RawMessage `json:"message"`
 }
 
+type MessageID struct {
+	UUID  string
+	Block int
+}
+
 type UserMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
@@ -36,21 +45,70 @@ type PreviewContent struct {
 }
 
 type PreviewMessage struct {
+	ID      MessageID
 	Role    string
 	Content string
 }
 
+func (previewContent *PreviewContent) MessageIndex(messageID MessageID) int {
+	for messageIndex, previewMessage := range previewContent.Messages {
+		if previewMessage.ID == messageID {
+			return messageIndex
+		}
+	}
+
+	return -1
+}
+
 func LoadSessionPreview(session *Session, maxMessages int) PreviewContent {
+	return loadSessionPreview(session, maxMessages, nil)
+}
+
+func LoadSessionPreviewAround(session *Session, maxMessages int, anchor MessageID) PreviewContent {
+	return loadSessionPreview(session, maxMessages, &anchor)
+}
+
+func loadSessionPreview(session *Session, maxMessages int, anchor *MessageID) PreviewContent {
 	if session == nil || session.FullPath == "" {
 		return PreviewContent{Error: "No session selected"}
 	}
 
-	file, openError := os.Open(session.FullPath)
+	messages, readError := readSessionMessages(session.FullPath, true)
 
-	if openError != nil {
+	if readError != nil && len(messages) == 0 {
 		return PreviewContent{Error: "Could not open session file"}
 	}
 
+	if len(messages) > maxMessages {
+		start := len(messages) - maxMessages
+
+		if anchor != nil {
+			for messageIndex, previewMessage := range messages {
+				if previewMessage.ID == *anchor {
+					start = min(max(0, messageIndex-maxMessages/2), len(messages)-maxMessages)
+
+					break
+				}
+			}
+		}
+
+		messages = messages[start : start+maxMessages]
+	}
+
+	if len(messages) == 0 {
+		return PreviewContent{Error: "No messages in session"}
+	}
+
+	return PreviewContent{Messages: messages}
+}
+
+func readSessionMessages(filePath string, truncateContent bool) ([]PreviewMessage, error) {
+	file, openError := os.Open(filePath)
+
+	if openError != nil {
+		return nil, openError
+	}
+
 	defer func() { _ = file.Close() }()
 
 	var messages []PreviewMessage
@@ -60,8 +118,11 @@ func LoadSessionPreview(session *Session, maxMessages int) PreviewContent {
 
 	scanner.Buffer(scanBuffer, 10*1024*1024)
 
+	lineNumber := 0
+
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNumber += 1
 
 		if line == "" {
 			continue
@@ -73,22 +134,28 @@ func LoadSessionPreview(session *Session, maxMessages int) PreviewContent {
 			continue
 		}
 
-		parsedMessages := parseRawMessage(rawMessage)
+		parsedMessages := parseRawMessage(rawMessage, lineNumber, truncateContent)
 		messages = append(messages, parsedMessages...)
 	}
 
-	if len(messages) > maxMessages {
-		messages = messages[len(messages)-maxMessages:]
-	}
+	return messages, scanner.Err()
+}
 
-	if len(messages) == 0 {
-		return PreviewContent{Error: "No messages in session"}
+func newMessageID(rawMessage *RawMessage, lineNumber, block int) MessageID {
+	uuid := rawMessage.UUID
+
+	if uuid == "" {
+		uuid = fmt.Sprintf("line:%d", lineNumber)
 	}
 
-	return PreviewContent{Messages: messages}
+	return MessageID{UUID: uuid, Block: block}
 }
 
-func parseRawMessage(rawMessage RawMessage) []PreviewMessage {
+func SetPreviewTruncation(length int) {
+	previewTruncateLength = length
+}
+
+func parseRawMessage(rawMessage RawMessage, lineNumber int, truncateContent bool) []PreviewMessage {
 	var result []PreviewMessage
 
 	switch rawMessage.Type {
@@ -101,12 +168,16 @@ func parseRawMessage(rawMessage RawMessage) []PreviewMessage {
 
 		content := userMessage.Content
 
-		if len(content) > 500 {
-			content = content[:500] + " …"
+		if truncateContent && len(content) > previewTruncateLength {
+			content = content[:previewTruncateLength] + " …"
 		}
 
 		if content != "" {
-			result = append(result, PreviewMessage{Role: "user", Content: content})
+			result = append(result, PreviewMessage{
+				ID:      newMessageID(&rawMessage, lineNumber, 0),
+				Role:    "user",
+				Content: content,
+			})
 		}
 	case "assistant":
 		var assistantMessage AssistantMessage
@@ -115,17 +186,19 @@ func parseRawMessage(rawMessage RawMessage) []PreviewMessage {
 			return nil
 		}
 
-		for _, contentBlock := range assistantMessage.Content {
+		for blockIndex, contentBlock := range assistantMessage.Content {
+			messageID := newMessageID(&rawMessage, lineNumber, blockIndex)
+
 			switch contentBlock.Type {
 			case "text":
 				text := contentBlock.Text
 
-				if len(text) > 500 {
-					text = text[:500] + " …"
+				if truncateContent && len(text) > previewTruncateLength {
+					text = text[:previewTruncateLength] + " …"
 				}
 
 				if text != "" {
-					result = append(result, PreviewMessage{Role: "assistant", Content: text})
+					result = append(result, PreviewMessage{ID: messageID, Role: "assistant", Content: text})
 				}
 			case "tool_use":
 				toolInfo := contentBlock.Name
@@ -152,17 +225,17 @@ func parseRawMessage(rawMessage RawMessage) []PreviewMessage {
 						}
 					}
 
-					result = append(result, PreviewMessage{Role: "tool", Content: toolInfo})
+					result = append(result, PreviewMessage{ID: messageID, Role: "tool", Content: toolInfo})
 				}
 			case "thinking":
 				thinking := contentBlock.Thinking
 
-				if len(thinking) > 200 {
+				if truncateContent && len(thinking) > 200 {
 					thinking = thinking[:200] + " …"
 				}
 
 				if thinking != "" {
-					result = append(result, PreviewMessage{Role: "thinking", Content: thinking})
+					result = append(result, PreviewMessage{ID: messageID, Role: "thinking", Content: thinking})
 				}
 			}
 		}
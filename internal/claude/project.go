@@ -0,0 +1,251 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type ProjectNameStyle int
+
+const (
+	ProjectNameShort ProjectNameStyle = iota
+	ProjectNameHome
+	ProjectNameGit
+)
+
+var projectNameStyleNames = []string{"short", "home", "git"}
+
+var (
+	projectNameStyle      = ProjectNameShort
+	projectNameCache      = map[string]string{}
+	decodedDirectoryCache = map[string]string{}
+	projectCacheMutex     sync.Mutex
+)
+
+func (style ProjectNameStyle) String() string {
+	if style < 0 || int(style) >= len(projectNameStyleNames) {
+		return projectNameStyleNames[ProjectNameShort]
+	}
+
+	return projectNameStyleNames[style]
+}
+
+func (style ProjectNameStyle) Next() ProjectNameStyle {
+	return ProjectNameStyle((int(style) + 1) % len(projectNameStyleNames))
+}
+
+func ParseProjectNameStyle(name string) (ProjectNameStyle, bool) {
+	for styleIndex, styleName := range projectNameStyleNames {
+		if styleName == name {
+			return ProjectNameStyle(styleIndex), true
+		}
+	}
+
+	return ProjectNameShort, false
+}
+
+func SetProjectNameStyle(style ProjectNameStyle) {
+	projectCacheMutex.Lock()
+
+	defer projectCacheMutex.Unlock()
+
+	if style != projectNameStyle {
+		projectNameStyle = style
+		projectNameCache = map[string]string{}
+	}
+}
+
+func RefreshProjectNames(sessions []Session) {
+	for sessionIndex := range sessions {
+		sessions[sessionIndex].ProjectName = ProjectDisplayName(sessions[sessionIndex].ResolvedPath)
+	}
+}
+
+func resolveProjectPath(projectDirectoryName, sessionPath, originalPath string) string {
+	if sessionPath != "" {
+		return sessionPath
+	}
+
+	if originalPath != "" {
+		return originalPath
+	}
+
+	return decodeProjectDirectoryName(projectDirectoryName)
+}
+
+func encodeProjectPath(projectPath string) string {
+	return strings.Map(func(character rune) rune {
+		if (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') ||
+			(character >= '0' && character <= '9') {
+			return character
+		}
+
+		return '-'
+	}, projectPath)
+}
+
+func decodeProjectDirectoryName(directoryName string) string {
+	projectCacheMutex.Lock()
+
+	cached, isCached := decodedDirectoryCache[directoryName]
+
+	projectCacheMutex.Unlock()
+
+	if isCached {
+		return cached
+	}
+
+	decoded := ""
+
+	if strings.HasPrefix(directoryName, "-") {
+		decoded = matchEncodedPath(string(filepath.Separator), directoryName[1:])
+	}
+
+	if decoded == "" {
+		decoded = strings.ReplaceAll(directoryName, "-", "/")
+	}
+
+	projectCacheMutex.Lock()
+
+	decodedDirectoryCache[directoryName] = decoded
+
+	projectCacheMutex.Unlock()
+
+	return decoded
+}
+
+func matchEncodedPath(basePath, remaining string) string {
+	if remaining == "" {
+		return basePath
+	}
+
+	directoryEntries, readError := os.ReadDir(basePath)
+
+	if readError != nil {
+		return ""
+	}
+
+	for _, directoryEntry := range directoryEntries {
+		if !directoryEntry.IsDir() {
+			continue
+		}
+
+		encodedName := encodeProjectPath(directoryEntry.Name())
+		candidatePath := filepath.Join(basePath, directoryEntry.Name())
+
+		if remaining == encodedName {
+			return candidatePath
+		}
+
+		if strings.HasPrefix(remaining, encodedName+"-") {
+			if matched := matchEncodedPath(candidatePath, remaining[len(encodedName)+1:]); matched != "" {
+				return matched
+			}
+		}
+	}
+
+	return ""
+}
+
+func ProjectDisplayName(projectPath string) string {
+	if projectPath == "" {
+		return ""
+	}
+
+	projectCacheMutex.Lock()
+
+	style := projectNameStyle
+	cached, isCached := projectNameCache[projectPath]
+
+	projectCacheMutex.Unlock()
+
+	if isCached {
+		return cached
+	}
+
+	var displayName string
+
+	switch style {
+	case ProjectNameHome:
+		displayName = homeRelativePath(projectPath)
+	case ProjectNameGit:
+		displayName = gitRepositoryName(projectPath)
+	default:
+		displayName = shortProjectName(projectPath)
+	}
+
+	projectCacheMutex.Lock()
+
+	projectNameCache[projectPath] = displayName
+
+	projectCacheMutex.Unlock()
+
+	return displayName
+}
+
+func shortProjectName(projectPath string) string {
+	cleanPath := filepath.Clean(projectPath)
+	base := filepath.Base(cleanPath)
+	parent := filepath.Base(filepath.Dir(cleanPath))
+
+	if parent == "" || parent == "." || parent == string(filepath.Separator) {
+		return base
+	}
+
+	return parent + "/" + base
+}
+
+func homeRelativePath(projectPath string) string {
+	homeDirectory, homeError := os.UserHomeDir()
+
+	if homeError != nil || homeDirectory == "" {
+		return projectPath
+	}
+
+	if projectPath == homeDirectory {
+		return "~"
+	}
+
+	if relativePath, isUnderHome := strings.CutPrefix(projectPath, homeDirectory+string(filepath.Separator)); isUnderHome {
+		return "~/" + filepath.ToSlash(relativePath)
+	}
+
+	return projectPath
+}
+
+func findGitRoot(projectPath string) string {
+	currentPath := filepath.Clean(projectPath)
+
+	for {
+		if _, statError := os.Stat(filepath.Join(currentPath, ".git")); statError == nil {
+			return currentPath
+		}
+
+		parentPath := filepath.Dir(currentPath)
+
+		if parentPath == currentPath {
+			return ""
+		}
+
+		currentPath = parentPath
+	}
+}
+
+func gitRepositoryName(projectPath string) string {
+	repositoryRoot := findGitRoot(projectPath)
+
+	if repositoryRoot == "" {
+		return shortProjectName(projectPath)
+	}
+
+	repositoryName := filepath.Base(repositoryRoot)
+	relativePath, relativeError := filepath.Rel(repositoryRoot, filepath.Clean(projectPath))
+
+	if relativeError != nil || relativePath == "." {
+		return repositoryName
+	}
+
+	return repositoryName + "/" + filepath.ToSlash(relativePath)
+}
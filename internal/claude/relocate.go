@@ -0,0 +1,233 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const relocateSearchDepth = 4
+
+type RelocationProposal struct {
+	OldPath      string
+	NewPath      string
+	SessionCount int
+	Reason       string
+	Alternatives []string
+}
+
+var (
+	relocateRoots   []string
+	pathExistsCache = map[string]bool{}
+	pathCacheMutex  sync.Mutex
+)
+
+var skippedDirectoryNames = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+	"Library":      true,
+}
+
+func SetRelocateRoots(roots []string) {
+	relocateRoots = roots
+}
+
+func RelocateRoots() []string {
+	if len(relocateRoots) > 0 {
+		return relocateRoots
+	}
+
+	homeDirectory, homeError := os.UserHomeDir()
+
+	if homeError != nil {
+		return nil
+	}
+
+	return []string{homeDirectory}
+}
+
+func ProjectPathExists(projectPath string) bool {
+	if projectPath == "" {
+		return true
+	}
+
+	pathCacheMutex.Lock()
+
+	exists, isCached := pathExistsCache[projectPath]
+
+	pathCacheMutex.Unlock()
+
+	if isCached {
+		return exists
+	}
+
+	fileInfo, statError := os.Stat(projectPath)
+	exists = statError == nil && fileInfo.IsDir()
+
+	pathCacheMutex.Lock()
+
+	pathExistsCache[projectPath] = exists
+
+	pathCacheMutex.Unlock()
+
+	return exists
+}
+
+func ResetPathCache() {
+	pathCacheMutex.Lock()
+
+	pathExistsCache = map[string]bool{}
+
+	pathCacheMutex.Unlock()
+}
+
+func (session *Session) IsProjectMissing() bool {
+	return session.ProjectPath != "" && !ProjectPathExists(session.ProjectPath)
+}
+
+func remoteRepositoryName(remoteURL string) string {
+	remoteURL = strings.TrimSuffix(strings.TrimSuffix(remoteURL, "/"), ".git")
+
+	if separatorIndex := strings.LastIndexAny(remoteURL, "/:"); separatorIndex != -1 {
+		return remoteURL[separatorIndex+1:]
+	}
+
+	return remoteURL
+}
+
+func sharedSuffixLength(firstPath, secondPath string) int {
+	firstParts := strings.Split(filepath.Clean(firstPath), string(filepath.Separator))
+	secondParts := strings.Split(filepath.Clean(secondPath), string(filepath.Separator))
+	length := 0
+
+	for length < len(firstParts) && length < len(secondParts) &&
+		firstParts[len(firstParts)-1-length] == secondParts[len(secondParts)-1-length] {
+		length += 1
+	}
+
+	return length
+}
+
+func rankCandidates(oldPath string, candidates []string) []string {
+	ranked := append([]string(nil), candidates...)
+
+	sort.SliceStable(ranked, func(first, second int) bool {
+		firstShared := sharedSuffixLength(oldPath, ranked[first])
+		secondShared := sharedSuffixLength(oldPath, ranked[second])
+
+		if firstShared != secondShared {
+			return firstShared > secondShared
+		}
+
+		return len(ranked[first]) < len(ranked[second])
+	})
+
+	return ranked
+}
+
+func ProposeRelocations(sessions []Session) []RelocationProposal {
+	missingCounts := map[string]int{}
+	wantedNames := map[string][]string{}
+
+	for sessionIndex := range sessions {
+		session := &sessions[sessionIndex]
+
+		if !session.IsProjectMissing() {
+			continue
+		}
+
+		if missingCounts[session.ProjectPath] == 0 {
+			name := filepath.Base(session.ProjectPath)
+			wantedNames[name] = append(wantedNames[name], session.ProjectPath)
+		}
+
+		missingCounts[session.ProjectPath] += 1
+	}
+
+	if len(missingCounts) == 0 {
+		return nil
+	}
+
+	remoteMatches := map[string][]string{}
+	nameMatches := map[string][]string{}
+
+	var walk func(directory string, depth int)
+
+	walk = func(directory string, depth int) {
+		directoryEntries, readError := os.ReadDir(directory)
+
+		if readError != nil {
+			return
+		}
+
+		for _, directoryEntry := range directoryEntries {
+			name := directoryEntry.Name()
+
+			if !directoryEntry.IsDir() || strings.HasPrefix(name, ".") || skippedDirectoryNames[name] {
+				continue
+			}
+
+			candidatePath := filepath.Join(directory, name)
+
+			if _, isWanted := wantedNames[name]; isWanted {
+				nameMatches[name] = append(nameMatches[name], candidatePath)
+			}
+
+			if _, statError := os.Stat(filepath.Join(candidatePath, ".git")); statError == nil {
+				remoteURL := readRemoteURL(resolveCommonDirectory(resolveGitDirectory(candidatePath)))
+
+				if remoteName := remoteRepositoryName(remoteURL); remoteName != "" && remoteName != name {
+					if _, isWanted := wantedNames[remoteName]; isWanted {
+						remoteMatches[remoteName] = append(remoteMatches[remoteName], candidatePath)
+					}
+				}
+			}
+
+			if depth < relocateSearchDepth {
+				walk(candidatePath, depth+1)
+			}
+		}
+	}
+
+	for _, root := range RelocateRoots() {
+		walk(root, 1)
+	}
+
+	var proposals []RelocationProposal
+
+	for name, oldPaths := range wantedNames {
+		candidates := nameMatches[name]
+		reason := "same folder name"
+
+		if len(candidates) == 0 {
+			candidates = remoteMatches[name]
+			reason = "same git remote"
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		for _, oldPath := range oldPaths {
+			candidates = rankCandidates(oldPath, candidates)
+			proposals = append(proposals, RelocationProposal{
+				OldPath:      oldPath,
+				NewPath:      candidates[0],
+				SessionCount: missingCounts[oldPath],
+				Reason:       reason,
+				Alternatives: candidates[1:],
+			})
+		}
+	}
+
+	sort.Slice(proposals, func(first, second int) bool {
+		return proposals[first].OldPath < proposals[second].OldPath
+	})
+
+	return proposals
+}
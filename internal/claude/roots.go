@@ -0,0 +1,312 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+type Root struct {
+	Label    string
+	Dir      string
+	TrashDir string
+}
+
+var configuredRoots []Root
+
+func NewRoot(label, directory, trashDirectory string) Root {
+	if label == "" {
+		label = RootLabel(directory)
+	}
+
+	if trashDirectory == "" {
+		trashDirectory = filepath.Join(directory, "faustus-trash")
+	}
+
+	return Root{Label: label, Dir: directory, TrashDir: trashDirectory}
+}
+
+func RootLabel(directory string) string {
+	label := strings.TrimLeft(filepath.Base(filepath.Clean(directory)), ".")
+
+	if label == "" || label == string(filepath.Separator) {
+		return "root"
+	}
+
+	return label
+}
+
+func DefaultClaudeDir() string {
+	if configDirectory := os.Getenv("CLAUDE_CONFIG_DIR"); configDirectory != "" {
+		return configDirectory
+	}
+
+	homeDirectory, _ := os.UserHomeDir()
+
+	return filepath.Join(homeDirectory, ".claude")
+}
+
+func SetRoots(roots []Root) {
+	configuredRoots = roots
+}
+
+func Roots() []Root {
+	if len(configuredRoots) > 0 {
+		return configuredRoots
+	}
+
+	return []Root{NewRoot("", DefaultClaudeDir(), "")}
+}
+
+func PrimaryRoot() Root {
+	return Roots()[0]
+}
+
+func HasMultipleRoots() bool {
+	return len(Roots()) > 1
+}
+
+func FindRoot(label string) (Root, bool) {
+	for _, root := range Roots() {
+		if root.Label == label {
+			return root, true
+		}
+	}
+
+	return Root{}, false
+}
+
+func RootForSession(session *Session) Root {
+	if root, found := FindRoot(session.Root); found {
+		return root
+	}
+
+	return PrimaryRoot()
+}
+
+func rootForPath(path string) Root {
+	for _, root := range Roots() {
+		if isWithin(path, root.Dir) || isWithin(path, root.TrashDir) {
+			return root
+		}
+	}
+
+	return PrimaryRoot()
+}
+
+func isWithin(path, directory string) bool {
+	relativePath, relativeError := filepath.Rel(directory, path)
+
+	return relativeError == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
+
+func (root Root) ProjectsDir() string {
+	return filepath.Join(root.Dir, "projects")
+}
+
+func sessionDirectories() []string {
+	var directories []string
+
+	for _, root := range Roots() {
+		directories = append(directories, root.ProjectsDir(), root.TrashDir)
+	}
+
+	return directories
+}
+
+func (root Root) sessionDirectory(inTrash bool) string {
+	if inTrash {
+		return root.TrashDir
+	}
+
+	return root.ProjectsDir()
+}
+
+func MoveToRoot(session *Session, destination Root) error {
+	plan, planError := PlanMoveToRoot(session, destination)
+
+	if planError != nil {
+		return planError
+	}
+
+	if _, applyError := plan.Apply(); applyError != nil {
+		return applyError
+	}
+
+	plan.syncSession(session)
+
+	return nil
+}
+
+func PlanMoveToRoot(session *Session, destination Root) (*Plan, error) {
+	return planTransferToRoot(session, destination, true)
+}
+
+func PlanCopyToRoot(session *Session, destination Root) (*Plan, error) {
+	return planTransferToRoot(session, destination, false)
+}
+
+func planTransferToRoot(session *Session, destination Root, removeSource bool) (*Plan, error) {
+	verb := "Copy"
+
+	if removeSource {
+		verb = "Move"
+	}
+
+	if session.Root == destination.Label {
+		return nil, fmt.Errorf("session is already in %s", destination.Label)
+	}
+
+	plan := &Plan{Description: verb + " session to " + destination.Label}
+
+	sourceProjectDirectory := ProjectDir(session)
+	destinationProjectDirectory := filepath.Join(destination.sessionDirectory(session.InTrash),
+		filepath.Base(sourceProjectDirectory))
+	destinationFile := filepath.Join(destinationProjectDirectory, session.SessionID+".jsonl")
+
+	if _, statError := os.Stat(destinationFile); statError == nil {
+		return nil, fmt.Errorf("session %s already exists in %s", session.SessionID, destination.Label)
+	}
+
+	transferredSession := *session
+	transferredSession.Root = destination.Label
+	transferredSession.FullPath = destinationFile
+	operation := newSessionOperation(verb+" "+session.SessionID+" to "+destination.Label, session)
+	operation.Steps = []Step{
+		{Kind: StepCreateDirectory, Path: destinationProjectDirectory},
+		{Kind: StepCopy, Path: session.FullPath, Destination: destinationFile},
+		{
+			Kind:        StepCopy,
+			Path:        filepath.Join(sourceProjectDirectory, session.SessionID),
+			Destination: filepath.Join(destinationProjectDirectory, session.SessionID),
+			Optional:    true,
+		},
+		{
+			Kind:      StepIndexAdd,
+			Path:      filepath.Join(destinationProjectDirectory, "sessions-index.json"),
+			SessionID: session.SessionID,
+			Value:     session.ProjectPath,
+			Entry:     &transferredSession,
+		},
+	}
+	operation.Steps = append(operation.Steps, metadataTransferSteps(session, destination.Label)...)
+
+	if removeSource {
+		operation.Steps = append(operation.Steps,
+			Step{Kind: StepRemove, Path: session.FullPath},
+			Step{Kind: StepRemove, Path: filepath.Join(sourceProjectDirectory, session.SessionID)},
+			Step{
+				Kind:      StepIndexRemove,
+				Path:      filepath.Join(sourceProjectDirectory, "sessions-index.json"),
+				SessionID: session.SessionID,
+			},
+			removeMetadataStep(session.Root, session.SessionID),
+		)
+
+		if session.InTrash {
+			operation.Steps = append(operation.Steps, Step{Kind: StepPruneDirectory, Path: sourceProjectDirectory})
+		}
+	}
+
+	plan.Add(operation)
+
+	return plan, nil
+}
+
+func copyPath(sourcePath, destinationPath string) error {
+	sourceInfo, statError := os.Lstat(sourcePath)
+
+	if statError != nil {
+		return statError
+	}
+
+	if !sourceInfo.IsDir() {
+		return copyFile(sourcePath, destinationPath, sourceInfo)
+	}
+
+	return filepath.Walk(sourcePath, func(walkedPath string, walkedInfo os.FileInfo, walkError error) error {
+		if walkError != nil {
+			return walkError
+		}
+
+		relativePath, relativeError := filepath.Rel(sourcePath, walkedPath)
+
+		if relativeError != nil {
+			return relativeError
+		}
+
+		targetPath := filepath.Join(destinationPath, relativePath)
+
+		if walkedInfo.IsDir() {
+			return os.MkdirAll(targetPath, walkedInfo.Mode().Perm())
+		}
+
+		if !walkedInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFile(walkedPath, targetPath, walkedInfo)
+	})
+}
+
+func copyFile(sourcePath, destinationPath string, sourceInfo os.FileInfo) error {
+	source, openError := os.Open(sourcePath)
+
+	if openError != nil {
+		return openError
+	}
+
+	defer func() { _ = source.Close() }()
+
+	destination, createError := os.OpenFile(destinationPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, sourceInfo.Mode().Perm())
+
+	if createError != nil {
+		return createError
+	}
+
+	defer func() { _ = destination.Close() }()
+
+	if _, copyError := io.Copy(destination, source); copyError != nil {
+		return copyError
+	}
+
+	if syncError := destination.Sync(); syncError != nil {
+		return syncError
+	}
+
+	return os.Chtimes(destinationPath, sourceInfo.ModTime(), sourceInfo.ModTime())
+}
+
+func replacePath(sourcePath, destinationPath string) error {
+	renameError := os.Rename(sourcePath, destinationPath)
+
+	if renameError == nil || !errors.Is(renameError, syscall.EXDEV) {
+		return renameError
+	}
+
+	if removeError := os.RemoveAll(destinationPath); removeError != nil {
+		return removeError
+	}
+
+	return movePath(sourcePath, destinationPath)
+}
+
+func movePath(sourcePath, destinationPath string) error {
+	renameError := os.Rename(sourcePath, destinationPath)
+
+	if renameError == nil || !errors.Is(renameError, syscall.EXDEV) {
+		return renameError
+	}
+
+	if copyError := copyPath(sourcePath, destinationPath); copyError != nil {
+		_ = os.RemoveAll(destinationPath)
+
+		return copyError
+	}
+
+	return os.RemoveAll(sourcePath)
+}
@@ -9,7 +9,7 @@ import (
 
 type SearchResult struct {
 	Session       *Session
-	MessageIndex  int
+	ID            MessageID
 	Role          string
 	Content       string
 	MatchPosition int
@@ -44,15 +44,25 @@ func searchSession(session *Session, query string) []SearchResult {
 
 	var results []SearchResult
 
+	if matchPosition := strings.Index(strings.ToLower(session.Note), query); matchPosition != -1 {
+		results = append(results, SearchResult{
+			Session:       session,
+			Role:          "note",
+			Content:       matchContext(session.Note, matchPosition, len(query)),
+			MatchPosition: matchPosition,
+		})
+	}
+
 	scanner := bufio.NewScanner(file)
 	scanBuffer := make([]byte, 0, 64*1024)
 
 	scanner.Buffer(scanBuffer, 10*1024*1024)
 
-	messageIndex := 0
+	lineNumber := 0
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNumber += 1
 
 		if line == "" {
 			continue
@@ -64,18 +74,14 @@ func searchSession(session *Session, query string) []SearchResult {
 			continue
 		}
 
-		matches := searchRawMessage(session, &rawMessage, query, messageIndex)
+		matches := searchRawMessage(session, &rawMessage, query, lineNumber)
 		results = append(results, matches...)
-
-		if rawMessage.Type == "user" || rawMessage.Type == "assistant" {
-			messageIndex += 1
-		}
 	}
 
 	return results
 }
 
-func searchRawMessage(session *Session, rawMessage *RawMessage, query string, messageIndex int) []SearchResult {
+func searchRawMessage(session *Session, rawMessage *RawMessage, query string, lineNumber int) []SearchResult {
 	var results []SearchResult
 
 	switch rawMessage.Type {
@@ -92,7 +98,7 @@ func searchRawMessage(session *Session, rawMessage *RawMessage, query string, me
 			content := matchContext(userMessage.Content, matchPosition, len(query))
 			results = append(results, SearchResult{
 				Session:       session,
-				MessageIndex:  messageIndex,
+				ID:            newMessageID(rawMessage, lineNumber, 0),
 				Role:          "user",
 				Content:       content,
 				MatchPosition: matchPosition,
@@ -105,7 +111,7 @@ func searchRawMessage(session *Session, rawMessage *RawMessage, query string, me
 			return nil
 		}
 
-		for _, contentBlock := range assistantMessage.Content {
+		for blockIndex, contentBlock := range assistantMessage.Content {
 			if contentBlock.Type == "text" {
 				textLowercase := strings.ToLower(contentBlock.Text)
 
@@ -113,7 +119,7 @@ func searchRawMessage(session *Session, rawMessage *RawMessage, query string, me
 					content := matchContext(contentBlock.Text, matchPosition, len(query))
 					results = append(results, SearchResult{
 						Session:       session,
-						MessageIndex:  messageIndex,
+						ID:            newMessageID(rawMessage, lineNumber, blockIndex),
 						Role:          "assistant",
 						Content:       content,
 						MatchPosition: matchPosition,
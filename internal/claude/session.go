@@ -3,13 +3,17 @@ package claude
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Fuwn/faustus/internal/fsutil"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 )
 
+var writeFileAtomic = fsutil.WriteFileAtomic
+
 type Session struct {
 	SessionID    string    `json:"sessionId"`
 	FullPath     string    `json:"fullPath"`
@@ -22,7 +26,22 @@ type Session struct {
 	ProjectPath  string    `json:"projectPath"`
 	IsSidechain  bool      `json:"isSidechain"`
 	ProjectName  string    `json:"-"`
+	ResolvedPath string    `json:"-"`
 	InTrash      bool      `json:"-"`
+	FileSize     int64     `json:"-"`
+	TokenUsage   int       `json:"-"`
+	Tags         []string  `json:"-"`
+	Pinned       bool      `json:"-"`
+	Note         string    `json:"-"`
+	Root         string    `json:"-"`
+}
+
+func (session *Session) Title() string {
+	if session.Summary != "" {
+		return session.Summary
+	}
+
+	return session.FirstPrompt
 }
 
 type SessionIndex struct {
@@ -32,17 +51,15 @@ type SessionIndex struct {
 }
 
 func ClaudeDir() string {
-	homeDirectory, _ := os.UserHomeDir()
-
-	return filepath.Join(homeDirectory, ".claude")
+	return PrimaryRoot().Dir
 }
 
 func ProjectsDir() string {
-	return filepath.Join(ClaudeDir(), "projects")
+	return PrimaryRoot().ProjectsDir()
 }
 
 func TrashDir() string {
-	return filepath.Join(ClaudeDir(), "faustus-trash")
+	return PrimaryRoot().TrashDir
 }
 
 func EnsureTrashDir() error {
@@ -52,7 +69,30 @@ func EnsureTrashDir() error {
 func LoadAllSessions() ([]Session, error) {
 	var allSessions []Session
 
-	projectsDirectory := ProjectsDir()
+	for rootIndex, root := range Roots() {
+		rootSessions, loadError := loadRootSessions(root)
+
+		if loadError != nil {
+			if rootIndex == 0 {
+				return nil, loadError
+			}
+
+			continue
+		}
+
+		allSessions = append(allSessions, rootSessions...)
+	}
+
+	applyMetadata(allSessions)
+	SortSessions(allSessions, SortModified, true)
+
+	return allSessions, nil
+}
+
+func loadRootSessions(root Root) ([]Session, error) {
+	var rootSessions []Session
+
+	projectsDirectory := root.ProjectsDir()
 	directoryEntries, readError := os.ReadDir(projectsDirectory)
 
 	if readError != nil {
@@ -72,10 +112,10 @@ func LoadAllSessions() ([]Session, error) {
 			sessions = loadSessionsFromJsonlFiles(projectDirectory, directoryEntry.Name(), false)
 		}
 
-		allSessions = append(allSessions, sessions...)
+		rootSessions = append(rootSessions, sessions...)
 	}
 
-	trashDirectory := TrashDir()
+	trashDirectory := root.TrashDir
 
 	if _, statError := os.Stat(trashDirectory); statError == nil {
 		trashEntries, readError := os.ReadDir(trashDirectory)
@@ -94,16 +134,16 @@ func LoadAllSessions() ([]Session, error) {
 					sessions = loadSessionsFromJsonlFiles(projectDirectory, directoryEntry.Name(), true)
 				}
 
-				allSessions = append(allSessions, sessions...)
+				rootSessions = append(rootSessions, sessions...)
 			}
 		}
 	}
 
-	sort.Slice(allSessions, func(first, second int) bool {
-		return allSessions[first].Modified.After(allSessions[second].Modified)
-	})
+	for sessionIndex := range rootSessions {
+		rootSessions[sessionIndex].Root = root.Label
+	}
 
-	return allSessions, nil
+	return rootSessions, nil
 }
 
 func loadSessionsFromIndex(indexPath, projectDirectoryName string, inTrash bool) ([]Session, error) {
@@ -119,15 +159,17 @@ func loadSessionsFromIndex(indexPath, projectDirectoryName string, inTrash bool)
 		return nil, unmarshalError
 	}
 
-	projectName := deriveProjectName(projectDirectoryName)
-
 	for entryIndex := range sessionIndex.Entries {
-		sessionIndex.Entries[entryIndex].ProjectName = projectName
+		resolvedPath := resolveProjectPath(projectDirectoryName, sessionIndex.Entries[entryIndex].ProjectPath,
+			sessionIndex.OriginalPath)
+		sessionIndex.Entries[entryIndex].ResolvedPath = resolvedPath
+		sessionIndex.Entries[entryIndex].ProjectName = ProjectDisplayName(resolvedPath)
 		sessionIndex.Entries[entryIndex].InTrash = inTrash
+		sessionIndex.Entries[entryIndex].FullPath = filepath.Join(filepath.Dir(indexPath),
+			sessionIndex.Entries[entryIndex].SessionID+".jsonl")
 
-		if inTrash {
-			sessionIndex.Entries[entryIndex].FullPath = filepath.Join(TrashDir(), projectDirectoryName,
-				sessionIndex.Entries[entryIndex].SessionID+".jsonl")
+		if fileInfo, statError := os.Stat(sessionIndex.Entries[entryIndex].FullPath); statError == nil {
+			sessionIndex.Entries[entryIndex].FileSize = fileInfo.Size()
 		}
 	}
 
@@ -148,6 +190,8 @@ type jsonlFirstLine struct {
 
 func loadSessionsFromJsonlFiles(projectDirectory, projectDirectoryName string, inTrash bool) []Session {
 	var sessions []Session
+	var leaves []string
+	var summaries []summaryRecord
 
 	entries, readError := os.ReadDir(projectDirectory)
 
@@ -155,29 +199,37 @@ func loadSessionsFromJsonlFiles(projectDirectory, projectDirectoryName string, i
 		return sessions
 	}
 
-	projectName := deriveProjectName(projectDirectoryName)
-
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
 			continue
 		}
 
 		fullPath := filepath.Join(projectDirectory, entry.Name())
-		session := parseSessionFromJsonl(fullPath, projectName, inTrash)
+		session, scan := scanSessionJsonl(fullPath, projectDirectoryName, inTrash)
+		summaries = append(summaries, scan.summaries...)
 
 		if session != nil {
 			sessions = append(sessions, *session)
+			leaves = append(leaves, scan.leafUUID)
 		}
 	}
 
+	linkSummaries(sessions, leaves, summaries)
+
 	return sessions
 }
 
-func parseSessionFromJsonl(filePath, projectName string, inTrash bool) *Session {
+func parseSessionFromJsonl(filePath, projectDirectoryName string, inTrash bool) *Session {
+	session, _ := scanSessionJsonl(filePath, projectDirectoryName, inTrash)
+
+	return session
+}
+
+func scanSessionJsonl(filePath, projectDirectoryName string, inTrash bool) (*Session, *titleScan) {
 	file, openError := os.Open(filePath)
 
 	if openError != nil {
-		return nil
+		return nil, &titleScan{}
 	}
 
 	defer func() { _ = file.Close() }()
@@ -185,7 +237,7 @@ func parseSessionFromJsonl(filePath, projectName string, inTrash bool) *Session
 	fileInfo, statError := file.Stat()
 
 	if statError != nil {
-		return nil
+		return nil, &titleScan{}
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -197,6 +249,7 @@ func parseSessionFromJsonl(filePath, projectName string, inTrash bool) *Session
 	var firstUserContent string
 	var messageCount int
 	var created time.Time
+	var scan titleScan
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -205,15 +258,14 @@ func parseSessionFromJsonl(filePath, projectName string, inTrash bool) *Session
 			continue
 		}
 
-		var raw struct {
-			Type      string    `json:"type"`
-			Timestamp time.Time `json:"timestamp"`
-		}
+		var raw titleLine
 
 		if unmarshalError := json.Unmarshal([]byte(line), &raw); unmarshalError != nil {
 			continue
 		}
 
+		scan.observe(&raw)
+
 		if raw.Type == "user" || raw.Type == "assistant" {
 			messageCount += 1
 		}
@@ -253,25 +305,28 @@ func parseSessionFromJsonl(filePath, projectName string, inTrash bool) *Session
 	}
 
 	if firstLine.SessionID == "" {
-		return nil
+		return nil, &scan
 	}
 
 	sessionID := strings.TrimSuffix(filepath.Base(filePath), ".jsonl")
+	resolvedPath := resolveProjectPath(projectDirectoryName, firstLine.Cwd, "")
 
 	return &Session{
 		SessionID:    sessionID,
 		FullPath:     filePath,
 		FirstPrompt:  truncateString(firstUserContent, 200),
-		Summary:      "",
+		Summary:      scan.title(),
 		MessageCount: messageCount,
 		Created:      created,
 		Modified:     fileInfo.ModTime(),
 		GitBranch:    firstLine.GitBranch,
 		ProjectPath:  firstLine.Cwd,
 		IsSidechain:  firstLine.IsSidechain,
-		ProjectName:  projectName,
+		ProjectName:  ProjectDisplayName(resolvedPath),
+		ResolvedPath: resolvedPath,
 		InTrash:      inTrash,
-	}
+		FileSize:     fileInfo.Size(),
+	}, &scan
 }
 
 func truncateString(text string, maxLength int) string {
@@ -282,24 +337,6 @@ func truncateString(text string, maxLength int) string {
 	return text[:maxLength-1] + "…"
 }
 
-func deriveProjectName(directoryName string) string {
-	parts := strings.Split(directoryName, "-")
-
-	if len(parts) > 0 {
-		for partIndex := len(parts) - 1; partIndex >= 0; partIndex-- {
-			if parts[partIndex] != "" {
-				if partIndex > 0 && len(parts[partIndex-1]) > 0 {
-					return parts[partIndex-1] + "/" + parts[partIndex]
-				}
-
-				return parts[partIndex]
-			}
-		}
-	}
-
-	return directoryName
-}
-
 func ProjectDir(session *Session) string {
 	return filepath.Dir(session.FullPath)
 }
@@ -309,40 +346,25 @@ func MoveToTrash(session *Session) error {
 		return nil
 	}
 
-	if ensureError := EnsureTrashDir(); ensureError != nil {
-		return ensureError
-	}
-
-	sourceProjectDirectory := ProjectDir(session)
-	projectDirectoryName := filepath.Base(sourceProjectDirectory)
-	destinationProjectDirectory := filepath.Join(TrashDir(), projectDirectoryName)
+	plan := PlanMoveToTrash(session)
 
-	if mkdirError := os.MkdirAll(destinationProjectDirectory, 0o755); mkdirError != nil {
-		return mkdirError
+	if _, applyError := plan.Apply(); applyError != nil {
+		return applyError
 	}
 
-	sourceFile := session.FullPath
-	destinationFile := filepath.Join(destinationProjectDirectory, session.SessionID+".jsonl")
-
-	if renameError := os.Rename(sourceFile, destinationFile); renameError != nil {
-		return renameError
-	}
+	plan.syncSession(session)
 
-	sourceAssociatedDirectory := filepath.Join(sourceProjectDirectory, session.SessionID)
+	return nil
+}
 
-	if _, statError := os.Stat(sourceAssociatedDirectory); statError == nil {
-		destinationAssociatedDirectory := filepath.Join(destinationProjectDirectory, session.SessionID)
-		_ = os.Rename(sourceAssociatedDirectory, destinationAssociatedDirectory)
-	}
+func PlanMoveToTrash(session *Session) *Plan {
+	plan := &Plan{Description: "Move session to the Bin"}
 
-	if removeError := removeFromIndex(sourceProjectDirectory, session.SessionID); removeError != nil {
-		return removeError
+	if !session.InTrash {
+		plan.Add(moveSessionOperation(session, RootForSession(session).TrashDir, true, "Move "+session.SessionID+" to the Bin"))
 	}
 
-	session.InTrash = true
-	session.FullPath = destinationFile
-
-	return addToIndex(destinationProjectDirectory, session)
+	return plan
 }
 
 func RestoreFromTrash(session *Session) error {
@@ -350,158 +372,285 @@ func RestoreFromTrash(session *Session) error {
 		return nil
 	}
 
-	sourceProjectDirectory := ProjectDir(session)
-	projectDirectoryName := filepath.Base(sourceProjectDirectory)
-	destinationProjectDirectory := filepath.Join(ProjectsDir(), projectDirectoryName)
+	plan := PlanRestoreFromTrash(session)
 
-	if mkdirError := os.MkdirAll(destinationProjectDirectory, 0o755); mkdirError != nil {
-		return mkdirError
+	if _, applyError := plan.Apply(); applyError != nil {
+		return applyError
 	}
 
-	sourceFile := session.FullPath
-	destinationFile := filepath.Join(destinationProjectDirectory, session.SessionID+".jsonl")
+	plan.syncSession(session)
+
+	return nil
+}
 
-	if renameError := os.Rename(sourceFile, destinationFile); renameError != nil {
-		return renameError
+func PlanRestoreFromTrash(session *Session) *Plan {
+	plan := &Plan{Description: "Restore session from the Bin"}
+
+	if session.InTrash {
+		plan.Add(moveSessionOperation(session, RootForSession(session).ProjectsDir(), false, "Restore "+session.SessionID))
 	}
 
+	return plan
+}
+
+func moveSessionOperation(session *Session, destinationRoot string, inTrash bool, description string) Operation {
+	sourceProjectDirectory := ProjectDir(session)
+	destinationProjectDirectory := filepath.Join(destinationRoot, filepath.Base(sourceProjectDirectory))
+	destinationFile := filepath.Join(destinationProjectDirectory, session.SessionID+".jsonl")
+	movedSession := *session
+	movedSession.InTrash = inTrash
+	movedSession.FullPath = destinationFile
+	operation := newSessionOperation(description, session)
+	operation.Steps = []Step{
+		{Kind: StepCreateDirectory, Path: destinationProjectDirectory},
+		{Kind: StepMove, Path: session.FullPath, Destination: destinationFile},
+	}
 	sourceAssociatedDirectory := filepath.Join(sourceProjectDirectory, session.SessionID)
 
 	if _, statError := os.Stat(sourceAssociatedDirectory); statError == nil {
-		destinationAssociatedDirectory := filepath.Join(destinationProjectDirectory, session.SessionID)
-		_ = os.Rename(sourceAssociatedDirectory, destinationAssociatedDirectory)
-	}
+		operation.Steps = append(operation.Steps, Step{
+			Kind:        StepMove,
+			Path:        sourceAssociatedDirectory,
+			Destination: filepath.Join(destinationProjectDirectory, session.SessionID),
+			Optional:    true,
+		})
+	}
+
+	operation.Steps = append(operation.Steps,
+		Step{Kind: StepIndexRemove, Path: filepath.Join(sourceProjectDirectory, "sessions-index.json"), SessionID: session.SessionID},
+		Step{
+			Kind:      StepIndexAdd,
+			Path:      filepath.Join(destinationProjectDirectory, "sessions-index.json"),
+			SessionID: session.SessionID,
+			Value:     session.ProjectPath,
+			Entry:     &movedSession,
+		},
+	)
 
-	if removeError := removeFromIndex(sourceProjectDirectory, session.SessionID); removeError != nil {
-		return removeError
+	if session.InTrash {
+		operation.Steps = append(operation.Steps, Step{Kind: StepPruneDirectory, Path: sourceProjectDirectory})
 	}
 
-	session.InTrash = false
-	session.FullPath = destinationFile
-
-	return addToIndex(destinationProjectDirectory, session)
+	return operation
 }
 
 func PermanentlyDelete(session *Session) error {
+	_, applyError := PlanPermanentlyDelete(session).Apply()
+
+	return applyError
+}
+
+func PlanPermanentlyDelete(session *Session) *Plan {
 	projectDirectory := ProjectDir(session)
+	plan := &Plan{Description: "Permanently delete session"}
 
-	if removeError := os.Remove(session.FullPath); removeError != nil && !os.IsNotExist(removeError) {
-		return removeError
+	operation := newSessionOperation("Delete "+session.SessionID, session)
+	operation.Steps = []Step{
+		{Kind: StepRemove, Path: session.FullPath},
+		{Kind: StepRemove, Path: filepath.Join(projectDirectory, session.SessionID)},
+		{Kind: StepIndexRemove, Path: filepath.Join(projectDirectory, "sessions-index.json"), SessionID: session.SessionID},
+		removeMetadataStep(session.Root, session.SessionID),
 	}
 
-	associatedDirectory := filepath.Join(projectDirectory, session.SessionID)
-	_ = os.RemoveAll(associatedDirectory)
+	plan.Add(operation)
 
-	return removeFromIndex(projectDirectory, session.SessionID)
+	return plan
 }
 
-func EmptyTrash() error {
-	trashDirectory := TrashDir()
+func EmptyTrash(includePinned bool) error {
+	_, applyError := PlanEmptyTrash(includePinned).Apply()
 
-	if _, statError := os.Stat(trashDirectory); os.IsNotExist(statError) {
-		return nil
+	return applyError
+}
+
+func PlanEmptyTrash(includePinned bool) *Plan {
+	plan := &Plan{Description: "Empty the Bin", ContinueOnError: true}
+	pinnedKeys := pinnedSessionKeys()
+
+	var keptSessions int
+
+	for _, root := range Roots() {
+		keptSessions += planEmptyRootTrash(plan, root, pinnedKeys, includePinned)
+	}
+
+	if keptSessions > 0 {
+		plan.Description = fmt.Sprintf("Empty the Bin, keeping %d pinned sessions", keptSessions)
 	}
 
-	return os.RemoveAll(trashDirectory)
+	return plan
 }
 
-func RenameSession(session *Session, newSummary string) error {
-	projectDirectory := ProjectDir(session)
-	indexPath := filepath.Join(projectDirectory, "sessions-index.json")
-	fileData, readError := os.ReadFile(indexPath)
+func planEmptyRootTrash(plan *Plan, root Root, pinnedKeys map[string]bool, includePinned bool) int {
+	trashDirectory := root.TrashDir
 
-	if readError != nil {
-		return readError
+	if _, statError := os.Stat(trashDirectory); statError != nil {
+		return 0
 	}
 
-	var sessionIndex SessionIndex
+	var keptSessions int
 
-	if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
-		return unmarshalError
-	}
+	var metadataSteps []Step
 
-	for entryIndex := range sessionIndex.Entries {
-		if sessionIndex.Entries[entryIndex].SessionID == session.SessionID {
-			sessionIndex.Entries[entryIndex].Summary = newSummary
+	deletePlan := &Plan{}
+	directoryEntries, _ := os.ReadDir(trashDirectory)
 
-			break
+	for _, directoryEntry := range directoryEntries {
+		if !directoryEntry.IsDir() {
+			continue
 		}
-	}
 
-	return writeIndex(indexPath, &sessionIndex)
-}
+		projectDirectory := filepath.Join(trashDirectory, directoryEntry.Name())
+		fileEntries, _ := os.ReadDir(projectDirectory)
+		keptInProject := false
 
-func removeFromIndex(projectDirectory, sessionID string) error {
-	indexPath := filepath.Join(projectDirectory, "sessions-index.json")
-	fileData, readError := os.ReadFile(indexPath)
+		for _, fileEntry := range fileEntries {
+			if fileEntry.IsDir() || !strings.HasSuffix(fileEntry.Name(), ".jsonl") {
+				continue
+			}
 
-	if readError != nil {
-		if os.IsNotExist(readError) {
-			return nil
+			session := Session{
+				SessionID: strings.TrimSuffix(fileEntry.Name(), ".jsonl"),
+				FullPath:  filepath.Join(projectDirectory, fileEntry.Name()),
+				Root:      root.Label,
+				InTrash:   true,
+			}
+			metadataSteps = append(metadataSteps, removeMetadataStep(root.Label, session.SessionID))
+
+			if pinnedKeys[metadataKey(root.Label, session.SessionID)] && !includePinned {
+				keptSessions += 1
+				keptInProject = true
+
+				continue
+			}
+
+			deletePlan.Merge(PlanPermanentlyDelete(&session))
 		}
 
-		return readError
+		if !keptInProject {
+			deletePlan.Add(Operation{
+				Description: "Remove empty project " + directoryEntry.Name(),
+				Steps:       []Step{{Kind: StepPruneDirectory, Path: projectDirectory}},
+			})
+		}
 	}
 
-	var sessionIndex SessionIndex
+	if keptSessions == 0 {
+		plan.Add(Operation{
+			Description: "Delete every session in the Bin",
+			Steps:       append([]Step{{Kind: StepRemove, Path: trashDirectory}}, metadataSteps...),
+		})
 
-	if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
-		return unmarshalError
+		return 0
 	}
 
-	if len(sessionIndex.Entries) == 0 {
-		return nil
-	}
+	plan.Merge(deletePlan)
 
-	filteredEntries := make([]Session, 0, len(sessionIndex.Entries))
+	return keptSessions
+}
 
-	for _, entry := range sessionIndex.Entries {
-		if entry.SessionID != sessionID {
-			filteredEntries = append(filteredEntries, entry)
-		}
+func RenameSession(session *Session, newSummary string) error {
+	_, applyError := PlanRenameSession(session, newSummary).Apply()
+
+	return applyError
+}
+
+func PlanRenameSession(session *Session, newSummary string) *Plan {
+	plan := &Plan{Description: "Rename session"}
+
+	indexPath := filepath.Join(ProjectDir(session), "sessions-index.json")
+	operation := newSessionOperation("Rename "+session.SessionID, session)
+
+	if !indexContainsSession(indexPath, session.SessionID) {
+		operation.Steps = append(operation.Steps, Step{Kind: StepRebuildIndex, Path: indexPath})
 	}
 
-	sessionIndex.Entries = filteredEntries
+	operation.Steps = append(operation.Steps, Step{
+		Kind:      StepIndexSummary,
+		Path:      indexPath,
+		SessionID: session.SessionID,
+		Value:     newSummary,
+	})
+
+	plan.Add(operation)
 
-	return writeIndex(indexPath, &sessionIndex)
+	return plan
+}
+
+func updateIndexSummary(indexPath, sessionID, newSummary string) error {
+	return updateIndex(indexPath, "", false, func(sessionIndex *SessionIndex) bool {
+		for entryIndex := range sessionIndex.Entries {
+			if sessionIndex.Entries[entryIndex].SessionID == sessionID {
+				sessionIndex.Entries[entryIndex].Summary = newSummary
+
+				return true
+			}
+		}
+
+		return false
+	})
 }
 
-func addToIndex(projectDirectory string, session *Session) error {
+func removeFromIndex(projectDirectory, sessionID string) error {
 	indexPath := filepath.Join(projectDirectory, "sessions-index.json")
+	updateError := updateIndex(indexPath, "", false, func(sessionIndex *SessionIndex) bool {
+		filteredEntries := make([]Session, 0, len(sessionIndex.Entries))
+
+		for _, entry := range sessionIndex.Entries {
+			if entry.SessionID != sessionID {
+				filteredEntries = append(filteredEntries, entry)
+			}
+		}
+
+		if len(filteredEntries) == len(sessionIndex.Entries) {
+			return false
+		}
+
+		sessionIndex.Entries = filteredEntries
+
+		return true
+	})
+
+	if os.IsNotExist(updateError) {
+		return nil
+	}
+
+	return updateError
+}
+
+func updateIndex(indexPath, originalPath string, createIfMissing bool, update func(*SessionIndex) bool) error {
+	unlock, lockError := lockDirectory(filepath.Dir(indexPath))
+
+	if lockError != nil {
+		return lockError
+	}
+
+	defer unlock()
 
 	var sessionIndex SessionIndex
 
+	stamp := stampFile(indexPath)
 	fileData, readError := os.ReadFile(indexPath)
 
 	if readError != nil {
-		if os.IsNotExist(readError) {
-			sessionIndex = SessionIndex{
-				Version:      1,
-				Entries:      []Session{},
-				OriginalPath: session.ProjectPath,
-			}
-		} else {
+		if !os.IsNotExist(readError) || !createIfMissing {
 			return readError
 		}
-	} else {
-		if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
-			return unmarshalError
+
+		sessionIndex = SessionIndex{
+			Version:      1,
+			Entries:      []Session{},
+			OriginalPath: originalPath,
 		}
+	} else if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
+		return unmarshalError
 	}
 
-	found := false
-
-	for entryIndex := range sessionIndex.Entries {
-		if sessionIndex.Entries[entryIndex].SessionID == session.SessionID {
-			sessionIndex.Entries[entryIndex] = *session
-			found = true
-
-			break
-		}
+	if !update(&sessionIndex) {
+		return nil
 	}
 
-	if !found {
-		sessionIndex.Entries = append(sessionIndex.Entries, *session)
+	if !stamp.matches(indexPath) {
+		return fmt.Errorf("%s: %w", indexPath, ErrFileChanged)
 	}
 
 	return writeIndex(indexPath, &sessionIndex)
@@ -514,205 +663,167 @@ func writeIndex(indexPath string, sessionIndex *SessionIndex) error {
 		return marshalError
 	}
 
-	return os.WriteFile(indexPath, jsonData, 0o644)
+	return writeFileAtomic(indexPath, jsonData, 0o644)
 }
 
 func ReassignSessionPath(session *Session, newPath string) error {
-	oldProjectDirectory := ProjectDir(session)
-	newDirectoryName := pathToDirectoryName(newPath)
-
-	var newProjectDirectory string
+	plan := PlanReassignSessionPath(session, newPath)
 
-	if session.InTrash {
-		newProjectDirectory = filepath.Join(TrashDir(), newDirectoryName)
-	} else {
-		newProjectDirectory = filepath.Join(ProjectsDir(), newDirectoryName)
+	if _, applyError := plan.Apply(); applyError != nil {
+		return applyError
 	}
 
-	if oldProjectDirectory == newProjectDirectory {
-		return nil
-	}
+	plan.syncSession(session)
 
-	if mkdirError := os.MkdirAll(newProjectDirectory, 0o755); mkdirError != nil {
-		return mkdirError
-	}
+	return nil
+}
 
-	oldJsonlPath := session.FullPath
-	newJsonlPath := filepath.Join(newProjectDirectory, filepath.Base(oldJsonlPath))
+func PlanReassignSessionPath(session *Session, newPath string) *Plan {
+	plan := &Plan{Description: "Reassign session to " + newPath}
 
-	if moveError := os.Rename(oldJsonlPath, newJsonlPath); moveError != nil {
-		return moveError
-	}
+	plan.Add(reassignSessionOperation(session, newPath))
 
-	if updateError := updateJsonlCwd(newJsonlPath, newPath); updateError != nil {
-		_ = os.Rename(newJsonlPath, oldJsonlPath)
+	return plan
+}
 
-		return updateError
+func reassignSessionOperation(session *Session, newPath string) Operation {
+	oldProjectDirectory := ProjectDir(session)
+	newProjectDirectory := filepath.Join(filepath.Dir(oldProjectDirectory), pathToDirectoryName(newPath))
+
+	if oldProjectDirectory == newProjectDirectory {
+		return Operation{}
 	}
 
-	oldIndexPath := filepath.Join(oldProjectDirectory, "sessions-index.json")
-	_ = removeFromIndex(oldProjectDirectory, session.SessionID)
-	newIndexPath := filepath.Join(newProjectDirectory, "sessions-index.json")
-	session.FullPath = newJsonlPath
-	session.ProjectPath = newPath
-	_ = addToIndexWithPath(newIndexPath, session, newPath)
+	newJsonlPath := filepath.Join(newProjectDirectory, filepath.Base(session.FullPath))
+	reassignedSession := *session
+	reassignedSession.FullPath = newJsonlPath
+	reassignedSession.ProjectPath = newPath
 
-	if isEmpty, _ := isDirectoryEmpty(oldProjectDirectory); isEmpty {
-		_ = os.Remove(oldIndexPath)
-		_ = os.Remove(oldProjectDirectory)
+	operation := newSessionOperation("Reassign "+session.SessionID+" to "+newPath, session)
+	operation.Steps = []Step{
+		{Kind: StepCreateDirectory, Path: newProjectDirectory},
+		{Kind: StepMove, Path: session.FullPath, Destination: newJsonlPath},
+		{Kind: StepRewriteCwd, Path: newJsonlPath, Value: newPath, Lines: countCwdLines(session.FullPath)},
+		{
+			Kind:      StepIndexRemove,
+			Path:      filepath.Join(oldProjectDirectory, "sessions-index.json"),
+			SessionID: session.SessionID,
+		},
+		{
+			Kind:      StepIndexAdd,
+			Path:      filepath.Join(newProjectDirectory, "sessions-index.json"),
+			SessionID: session.SessionID,
+			Value:     newPath,
+			Entry:     &reassignedSession,
+		},
+		{Kind: StepPruneDirectory, Path: oldProjectDirectory},
 	}
 
-	return nil
+	return operation
 }
 
 func ReassignProjectPath(oldPath, newPath string) (int, error) {
-	var updatedCount int
+	plan, planError := PlanReassignProjectPath(oldPath, newPath)
 
-	projectsDirectory := ProjectsDir()
-	directoryEntries, readError := os.ReadDir(projectsDirectory)
-
-	if readError != nil {
-		return 0, readError
+	if planError != nil {
+		return 0, planError
 	}
 
-	for _, directoryEntry := range directoryEntries {
-		if !directoryEntry.IsDir() {
-			continue
-		}
+	return plan.Apply()
+}
 
-		projectDirectory := filepath.Join(projectsDirectory, directoryEntry.Name())
-		count, updateError := reassignInProject(projectDirectory, oldPath, newPath, false)
+func PlanReassignProjectPath(oldPath, newPath string) (*Plan, error) {
+	if oldPath == "" {
+		return nil, errors.New("sessions without a project path cannot be reassigned together")
+	}
 
-		if updateError != nil {
-			return updatedCount, updateError
-		}
+	plan := &Plan{Description: "Reassign " + oldPath + " to " + newPath, ContinueOnError: true}
 
-		updatedCount += count
+	if _, readError := os.ReadDir(ProjectsDir()); readError != nil {
+		return nil, readError
 	}
 
-	trashDirectory := TrashDir()
+	for _, root := range Roots() {
+		planReassignInRoot(plan, root, oldPath, newPath)
+	}
 
-	if _, statError := os.Stat(trashDirectory); statError == nil {
-		trashEntries, readError := os.ReadDir(trashDirectory)
+	return plan, nil
+}
 
-		if readError == nil {
-			for _, directoryEntry := range trashEntries {
-				if !directoryEntry.IsDir() {
-					continue
-				}
+func planReassignInRoot(plan *Plan, root Root, oldPath, newPath string) {
+	projectsDirectory := root.ProjectsDir()
+	directoryEntries, _ := os.ReadDir(projectsDirectory)
 
-				projectDirectory := filepath.Join(trashDirectory, directoryEntry.Name())
-				count, updateError := reassignInProject(projectDirectory, oldPath, newPath, true)
+	for _, directoryEntry := range directoryEntries {
+		if !directoryEntry.IsDir() {
+			continue
+		}
 
-				if updateError != nil {
-					return updatedCount, updateError
-				}
+		planReassignInProject(plan, filepath.Join(projectsDirectory, directoryEntry.Name()), oldPath, newPath, false)
+	}
+
+	trashDirectory := root.TrashDir
 
-				updatedCount += count
+	if trashEntries, readError := os.ReadDir(trashDirectory); readError == nil {
+		for _, directoryEntry := range trashEntries {
+			if !directoryEntry.IsDir() {
+				continue
 			}
+
+			planReassignInProject(plan, filepath.Join(trashDirectory, directoryEntry.Name()), oldPath, newPath, true)
 		}
 	}
-
-	return updatedCount, nil
 }
 
-func reassignInProject(projectDirectory, oldPath, newPath string, inTrash bool) (int, error) {
+func planReassignInProject(plan *Plan, projectDirectory, oldPath, newPath string, inTrash bool) {
 	indexPath := filepath.Join(projectDirectory, "sessions-index.json")
 	fileData, readError := os.ReadFile(indexPath)
 
 	if readError != nil {
 		if os.IsNotExist(readError) {
-			return reassignOrphanedSessions(projectDirectory, oldPath, newPath, inTrash)
+			planReassignOrphanedSessions(plan, projectDirectory, oldPath, newPath, inTrash)
 		}
 
-		return 0, nil
+		return
 	}
 
 	var sessionIndex SessionIndex
 
 	if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
-		return 0, nil
-	}
-
-	if sessionIndex.OriginalPath != oldPath {
-		hasMatchingSessions := false
-
-		for _, entry := range sessionIndex.Entries {
-			if entry.ProjectPath == oldPath {
-				hasMatchingSessions = true
-
-				break
-			}
-		}
-
-		if !hasMatchingSessions {
-			return 0, nil
-		}
+		return
 	}
 
-	var updatedCount int
-
 	for _, entry := range sessionIndex.Entries {
 		if entry.ProjectPath == oldPath {
 			entry.InTrash = inTrash
 
-			if reassignError := ReassignSessionPath(&entry, newPath); reassignError != nil {
-				continue
-			}
-
-			updatedCount += 1
+			plan.Add(reassignSessionOperation(&entry, newPath))
 		}
 	}
-
-	if isEmpty, _ := isDirectoryEmpty(projectDirectory); isEmpty {
-		_ = os.Remove(indexPath)
-		_ = os.Remove(projectDirectory)
-	}
-
-	return updatedCount, nil
 }
 
-func reassignOrphanedSessions(projectDirectory, oldPath, newPath string, inTrash bool) (int, error) {
+func planReassignOrphanedSessions(plan *Plan, projectDirectory, oldPath, newPath string, inTrash bool) {
 	entries, readError := os.ReadDir(projectDirectory)
 
 	if readError != nil {
-		return 0, nil
+		return
 	}
 
-	var updatedCount int
-
-	projectName := deriveProjectName(filepath.Base(projectDirectory))
-
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
 			continue
 		}
 
 		fullPath := filepath.Join(projectDirectory, entry.Name())
-		currentPath := getJsonlProjectPath(fullPath)
 
-		if currentPath != oldPath {
+		if getJsonlProjectPath(fullPath) != oldPath {
 			continue
 		}
 
-		session := parseSessionFromJsonl(fullPath, projectName, inTrash)
-
-		if session == nil {
-			continue
+		if session := parseSessionFromJsonl(fullPath, filepath.Base(projectDirectory), inTrash); session != nil {
+			plan.Add(reassignSessionOperation(session, newPath))
 		}
-
-		if reassignError := ReassignSessionPath(session, newPath); reassignError != nil {
-			continue
-		}
-
-		updatedCount += 1
 	}
-
-	if isEmpty, _ := isDirectoryEmpty(projectDirectory); isEmpty {
-		_ = os.Remove(projectDirectory)
-	}
-
-	return updatedCount, nil
 }
 
 func getJsonlProjectPath(filePath string) string {
@@ -754,6 +865,15 @@ func getJsonlProjectPath(filePath string) string {
 }
 
 func updateJsonlProjectPath(filePath, newPath string) error {
+	unlock, lockError := lockDirectory(filepath.Dir(filePath))
+
+	if lockError != nil {
+		return lockError
+	}
+
+	defer unlock()
+
+	stamp := stampFile(filePath)
 	fileData, readError := os.ReadFile(filePath)
 
 	if readError != nil {
@@ -794,61 +914,56 @@ func updateJsonlProjectPath(filePath, newPath string) error {
 		updatedLines = append(updatedLines, string(updatedLine))
 	}
 
-	tempPath := filePath + ".tmp"
+	if !stamp.matches(filePath) {
+		return fmt.Errorf("%s: %w", filePath, ErrFileChanged)
+	}
 
-	if writeError := os.WriteFile(tempPath, []byte(strings.Join(updatedLines, "\n")), 0o644); writeError != nil {
+	if writeError := writeFileAtomic(filePath, []byte(strings.Join(updatedLines, "\n")), 0o644); writeError != nil {
 		return writeError
 	}
 
-	return os.Rename(tempPath, filePath)
+	// Restoring the modification time keeps the session sort order and activity detection stable.
+	return os.Chtimes(filePath, time.Time{}, stamp.modified)
 }
 
 func pathToDirectoryName(projectPath string) string {
-	return strings.ReplaceAll(projectPath, "/", "-")
+	return encodeProjectPath(projectPath)
 }
 
-func updateJsonlCwd(filePath, newPath string) error {
-	return updateJsonlProjectPath(filePath, newPath)
+func ProjectDirForPath(root Root, projectPath string, inTrash bool) string {
+	return filepath.Join(root.sessionDirectory(inTrash), pathToDirectoryName(projectPath))
 }
 
-func addToIndexWithPath(indexPath string, session *Session, originalPath string) error {
-	var sessionIndex SessionIndex
-
-	fileData, readError := os.ReadFile(indexPath)
+func CountSessionFiles(projectDirectory string) int {
+	entries, readError := os.ReadDir(projectDirectory)
 
 	if readError != nil {
-		if os.IsNotExist(readError) {
-			sessionIndex = SessionIndex{
-				Version:      1,
-				Entries:      []Session{},
-				OriginalPath: originalPath,
-			}
-		} else {
-			return readError
-		}
-	} else {
-		if unmarshalError := json.Unmarshal(fileData, &sessionIndex); unmarshalError != nil {
-			return unmarshalError
-		}
+		return 0
 	}
 
-	for _, entry := range sessionIndex.Entries {
-		if entry.SessionID == session.SessionID {
-			return nil
+	var count int
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".jsonl") {
+			count += 1
 		}
 	}
 
-	sessionIndex.Entries = append(sessionIndex.Entries, *session)
-
-	return writeIndex(indexPath, &sessionIndex)
+	return count
 }
 
-func isDirectoryEmpty(path string) (bool, error) {
-	entries, readError := os.ReadDir(path)
+func addToIndexWithPath(indexPath string, session *Session, originalPath string) error {
+	return updateIndex(indexPath, originalPath, true, func(sessionIndex *SessionIndex) bool {
+		for entryIndex := range sessionIndex.Entries {
+			if sessionIndex.Entries[entryIndex].SessionID == session.SessionID {
+				sessionIndex.Entries[entryIndex] = *session
 
-	if readError != nil {
-		return false, readError
-	}
+				return true
+			}
+		}
+
+		sessionIndex.Entries = append(sessionIndex.Entries, *session)
 
-	return len(entries) == 0, nil
+		return true
+	})
 }
@@ -0,0 +1,103 @@
+package claude
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errSimulatedWrite = errors.New("simulated write failure")
+
+func failAtomicWrites(t *testing.T) *[]byte {
+	t.Helper()
+
+	var attemptedData []byte
+
+	originalWrite := writeFileAtomic
+	writeFileAtomic = func(filePath string, data []byte, permissions os.FileMode) error {
+		attemptedData = data
+
+		return errSimulatedWrite
+	}
+
+	t.Cleanup(func() { writeFileAtomic = originalWrite })
+
+	return &attemptedData
+}
+
+func writeFixture(t *testing.T, name, contents string) (string, time.Time) {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), name)
+	modified := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	if writeError := os.WriteFile(filePath, []byte(contents), 0o644); writeError != nil {
+		t.Fatal(writeError)
+	}
+
+	if chtimesError := os.Chtimes(filePath, time.Time{}, modified); chtimesError != nil {
+		t.Fatal(chtimesError)
+	}
+
+	return filePath, modified
+}
+
+func assertFileUnchanged(t *testing.T, filePath, contents string, modified time.Time) {
+	t.Helper()
+
+	fileInfo, statError := os.Stat(filePath)
+
+	if statError != nil {
+		t.Fatal(statError)
+	}
+
+	fileData, readError := os.ReadFile(filePath)
+
+	if readError != nil {
+		t.Fatal(readError)
+	}
+
+	if !bytes.Equal(fileData, []byte(contents)) {
+		t.Fatalf("file changed to %q", fileData)
+	}
+
+	if !fileInfo.ModTime().Equal(modified) {
+		t.Fatalf("modification time changed from %s to %s", modified, fileInfo.ModTime())
+	}
+}
+
+func TestUpdateJsonlProjectPathFailureKeepsOriginal(t *testing.T) {
+	contents := `{"type":"user","cwd":"/old/project","sessionId":"s1"}` + "\n"
+	filePath, modified := writeFixture(t, "s1.jsonl", contents)
+	attemptedData := failAtomicWrites(t)
+
+	if updateError := updateJsonlProjectPath(filePath, "/new/project"); !errors.Is(updateError, errSimulatedWrite) {
+		t.Fatalf("expected the simulated write failure, got %v", updateError)
+	}
+
+	if !strings.Contains(string(*attemptedData), `"cwd":"/new/project"`) {
+		t.Fatalf("expected the rewritten cwd to reach the writer, got %q", *attemptedData)
+	}
+
+	assertFileUnchanged(t, filePath, contents, modified)
+}
+
+func TestUpdateIndexFailureKeepsOriginal(t *testing.T) {
+	contents := `{"version":1,"entries":[{"sessionId":"s1","summary":"Old title"}],"originalPath":"/old/project"}`
+	indexPath, modified := writeFixture(t, "sessions-index.json", contents)
+	attemptedData := failAtomicWrites(t)
+
+	if updateError := updateIndexSummary(indexPath, "s1", "New title"); !errors.Is(updateError, errSimulatedWrite) {
+		t.Fatalf("expected the simulated write failure, got %v", updateError)
+	}
+
+	if !strings.Contains(string(*attemptedData), "New title") {
+		t.Fatalf("expected the new summary to reach the writer, got %q", *attemptedData)
+	}
+
+	assertFileUnchanged(t, indexPath, contents, modified)
+}
@@ -0,0 +1,84 @@
+package claude
+
+import (
+	"sort"
+	"strings"
+)
+
+type SortField int
+
+const (
+	SortModified SortField = iota
+	SortCreated
+	SortMessages
+	SortTokens
+	SortProject
+	SortSummary
+	SortSize
+)
+
+var sortFieldNames = []string{"modified", "created", "messages", "tokens", "project", "summary", "size"}
+
+func (sortField SortField) String() string {
+	if sortField < 0 || int(sortField) >= len(sortFieldNames) {
+		return sortFieldNames[SortModified]
+	}
+
+	return sortFieldNames[sortField]
+}
+
+func (sortField SortField) Next() SortField {
+	return SortField((int(sortField) + 1) % len(sortFieldNames))
+}
+
+func ParseSortField(name string) (SortField, bool) {
+	for fieldIndex, fieldName := range sortFieldNames {
+		if fieldName == name {
+			return SortField(fieldIndex), true
+		}
+	}
+
+	return SortModified, false
+}
+
+func SortSessions(sessions []Session, sortField SortField, descending bool) {
+	compare := func(first, second *Session) int {
+		switch sortField {
+		case SortCreated:
+			return first.Created.Compare(second.Created)
+		case SortMessages:
+			return first.MessageCount - second.MessageCount
+		case SortTokens:
+			return first.TokenUsage - second.TokenUsage
+		case SortProject:
+			return strings.Compare(strings.ToLower(first.ProjectName), strings.ToLower(second.ProjectName))
+		case SortSummary:
+			return strings.Compare(strings.ToLower(first.Title()), strings.ToLower(second.Title()))
+		case SortSize:
+			switch {
+			case first.FileSize < second.FileSize:
+				return -1
+			case first.FileSize > second.FileSize:
+				return 1
+			}
+
+			return 0
+		default:
+			return first.Modified.Compare(second.Modified)
+		}
+	}
+
+	sort.SliceStable(sessions, func(first, second int) bool {
+		comparison := compare(&sessions[first], &sessions[second])
+
+		if comparison == 0 {
+			return sessions[first].Modified.After(sessions[second].Modified)
+		}
+
+		if descending {
+			return comparison > 0
+		}
+
+		return comparison < 0
+	})
+}
@@ -0,0 +1,85 @@
+package claude
+
+type summaryRecord struct {
+	leafUUID string
+	summary  string
+}
+
+type titleScan struct {
+	customTitle string
+	summaries   []summaryRecord
+	messageIDs  map[string]bool
+	leafUUID    string
+}
+
+type titleLine struct {
+	Type        string `json:"type"`
+	UUID        string `json:"uuid"`
+	Summary     string `json:"summary"`
+	LeafUUID    string `json:"leafUuid"`
+	CustomTitle string `json:"customTitle"`
+	IsSidechain bool   `json:"isSidechain"`
+}
+
+func (scan *titleScan) observe(line *titleLine) {
+	switch line.Type {
+	case "summary":
+		if line.Summary != "" && line.LeafUUID != "" {
+			scan.summaries = append(scan.summaries, summaryRecord{leafUUID: line.LeafUUID, summary: line.Summary})
+		}
+	case "custom-title":
+		if line.CustomTitle != "" {
+			scan.customTitle = line.CustomTitle
+		}
+	case "user", "assistant":
+		if line.UUID == "" {
+			return
+		}
+
+		if scan.messageIDs == nil {
+			scan.messageIDs = map[string]bool{}
+		}
+
+		scan.messageIDs[line.UUID] = true
+
+		if !line.IsSidechain {
+			scan.leafUUID = line.UUID
+		}
+	}
+}
+
+func (scan *titleScan) title() string {
+	if scan.customTitle != "" {
+		return scan.customTitle
+	}
+
+	var ownSummary string
+
+	for _, record := range scan.summaries {
+		if record.leafUUID == scan.leafUUID {
+			return record.summary
+		}
+
+		if scan.messageIDs[record.leafUUID] {
+			ownSummary = record.summary
+		}
+	}
+
+	return ownSummary
+}
+
+func linkSummaries(sessions []Session, leaves []string, summaries []summaryRecord) {
+	summariesByLeaf := map[string]string{}
+
+	for _, record := range summaries {
+		summariesByLeaf[record.leafUUID] = record.summary
+	}
+
+	for sessionIndex := range sessions {
+		if sessions[sessionIndex].Summary != "" || leaves[sessionIndex] == "" {
+			continue
+		}
+
+		sessions[sessionIndex].Summary = summariesByLeaf[leaves[sessionIndex]]
+	}
+}
@@ -0,0 +1,363 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+type appliedStep struct {
+	Step    int    `json:"step"`
+	Backup  string `json:"backup,omitempty"`
+	Existed bool   `json:"existed,omitempty"`
+}
+
+type transaction struct {
+	ID        string        `json:"id"`
+	ProcessID int           `json:"processId"`
+	Started   time.Time     `json:"started"`
+	Journal   string        `json:"journal"`
+	Operation Operation     `json:"operation"`
+	Applied   []appliedStep `json:"applied"`
+	Committed bool          `json:"committed"`
+}
+
+func JournalDir() string {
+	return PrimaryRoot().JournalDir()
+}
+
+func (root Root) JournalDir() string {
+	return filepath.Join(root.Dir, "faustus", "journal")
+}
+
+func journalDirectories() []string {
+	var directories []string
+
+	for _, root := range Roots() {
+		if !slices.Contains(directories, root.JournalDir()) {
+			directories = append(directories, root.JournalDir())
+		}
+	}
+
+	return directories
+}
+
+func (currentTransaction *transaction) journalPath() string {
+	return filepath.Join(currentTransaction.Journal, currentTransaction.ID+".json")
+}
+
+func (currentTransaction *transaction) stagingDirectory(path string) string {
+	return filepath.Join(rootForPath(path).JournalDir(), currentTransaction.ID)
+}
+
+func (currentTransaction *transaction) save() error {
+	jsonData, marshalError := json.MarshalIndent(currentTransaction, "", "  ")
+
+	if marshalError != nil {
+		return marshalError
+	}
+
+	return writeFileAtomic(currentTransaction.journalPath(), jsonData, 0o644)
+}
+
+func (currentTransaction *transaction) discard() {
+	stagingDirectories := []string{filepath.Join(currentTransaction.Journal, currentTransaction.ID)}
+
+	for _, record := range currentTransaction.Applied {
+		if record.Backup != "" && !slices.Contains(stagingDirectories, filepath.Dir(record.Backup)) {
+			stagingDirectories = append(stagingDirectories, filepath.Dir(record.Backup))
+		}
+	}
+
+	for _, stagingDirectory := range stagingDirectories {
+		_ = os.RemoveAll(stagingDirectory)
+	}
+
+	_ = os.Remove(currentTransaction.journalPath())
+}
+
+func beginTransaction(operation *Operation) (*transaction, error) {
+	now := time.Now()
+	currentTransaction := &transaction{
+		ID:        fmt.Sprintf("%d-%d", now.UnixNano(), os.Getpid()),
+		ProcessID: os.Getpid(),
+		Started:   now,
+		Journal:   rootForPath(operationPath(operation)).JournalDir(),
+		Operation: *operation,
+	}
+
+	if mkdirError := os.MkdirAll(currentTransaction.Journal, 0o755); mkdirError != nil {
+		return nil, mkdirError
+	}
+
+	if saveError := currentTransaction.save(); saveError != nil {
+		currentTransaction.discard()
+
+		return nil, saveError
+	}
+
+	return currentTransaction, nil
+}
+
+func operationPath(operation *Operation) string {
+	if operation.SessionPath != "" || len(operation.Steps) == 0 {
+		return operation.SessionPath
+	}
+
+	return operation.Steps[0].Path
+}
+
+func applyOperation(operation *Operation) error {
+	currentTransaction, beginError := beginTransaction(operation)
+
+	if beginError != nil {
+		return beginError
+	}
+
+	for stepIndex := range operation.Steps {
+		if stepError := currentTransaction.applyStep(stepIndex); stepError != nil {
+			return currentTransaction.abort(stepError)
+		}
+	}
+
+	currentTransaction.Committed = true
+
+	if saveError := currentTransaction.save(); saveError != nil {
+		return currentTransaction.abort(saveError)
+	}
+
+	currentTransaction.discard()
+
+	return nil
+}
+
+func (currentTransaction *transaction) applyStep(stepIndex int) error {
+	step := &currentTransaction.Operation.Steps[stepIndex]
+	record := appliedStep{Step: stepIndex}
+
+	switch step.Kind {
+	case StepCreateDirectory:
+		_, statError := os.Stat(step.Path)
+		record.Existed = statError == nil
+	case StepCopy:
+		_, statError := os.Stat(step.Destination)
+		record.Existed = statError == nil
+	case StepRemove, StepPruneDirectory:
+		record.Backup = filepath.Join(currentTransaction.stagingDirectory(step.Path), fmt.Sprint(stepIndex))
+
+		if mkdirError := os.MkdirAll(filepath.Dir(record.Backup), 0o755); mkdirError != nil {
+			return mkdirError
+		}
+	case StepRewriteCwd, StepIndexAdd, StepIndexRemove, StepIndexSummary, StepRebuildIndex, StepSetTags, StepSetPinned,
+		StepSetNote, StepRemoveMetadata, StepExport, StepFork:
+		record.Backup = filepath.Join(currentTransaction.stagingDirectory(step.target()), fmt.Sprint(stepIndex))
+
+		if mkdirError := os.MkdirAll(filepath.Dir(record.Backup), 0o755); mkdirError != nil {
+			return mkdirError
+		}
+
+		existed, backupError := backupFile(step.target(), record.Backup)
+
+		if backupError != nil {
+			return backupError
+		}
+
+		record.Existed = existed
+	}
+
+	currentTransaction.Applied = append(currentTransaction.Applied, record)
+
+	if saveError := currentTransaction.save(); saveError != nil {
+		return saveError
+	}
+
+	switch step.Kind {
+	case StepRemove:
+		if _, statError := os.Stat(step.Path); os.IsNotExist(statError) {
+			return nil
+		}
+
+		return movePath(step.Path, record.Backup)
+	case StepPruneDirectory:
+		if !isPrunable(step.Path) {
+			return nil
+		}
+
+		return movePath(step.Path, record.Backup)
+	}
+
+	return applyStep(step)
+}
+
+func (currentTransaction *transaction) abort(cause error) error {
+	if rollbackError := currentTransaction.rollback(); rollbackError != nil {
+		return fmt.Errorf("%w; rolling back failed, so %s was kept for recovery: %w", cause,
+			currentTransaction.journalPath(), rollbackError)
+	}
+
+	return cause
+}
+
+func (currentTransaction *transaction) rollback() error {
+	var undoErrors []error
+
+	for recordIndex := len(currentTransaction.Applied) - 1; recordIndex >= 0; recordIndex -= 1 {
+		record := currentTransaction.Applied[recordIndex]
+		step := &currentTransaction.Operation.Steps[record.Step]
+
+		if undoError := undoStep(step, record); undoError != nil {
+			undoErrors = append(undoErrors, fmt.Errorf("undo %s: %w", step, undoError))
+		}
+	}
+
+	if len(undoErrors) > 0 {
+		return errors.Join(undoErrors...)
+	}
+
+	currentTransaction.discard()
+
+	return nil
+}
+
+func undoStep(step *Step, record appliedStep) error {
+	switch step.Kind {
+	case StepCreateDirectory:
+		if !record.Existed {
+			return removeIfExists(step.Path)
+		}
+	case StepMove:
+		_, sourceError := os.Stat(step.Path)
+		_, destinationError := os.Stat(step.Destination)
+
+		if os.IsNotExist(sourceError) && destinationError == nil {
+			return movePath(step.Destination, step.Path)
+		}
+	case StepCopy:
+		if !record.Existed {
+			return os.RemoveAll(step.Destination)
+		}
+	case StepRemove, StepPruneDirectory:
+		if _, statError := os.Stat(record.Backup); statError == nil {
+			return movePath(record.Backup, step.Path)
+		}
+	case StepRewriteCwd, StepIndexAdd, StepIndexRemove, StepIndexSummary, StepRebuildIndex, StepSetTags, StepSetPinned,
+		StepSetNote, StepRemoveMetadata, StepExport, StepFork:
+		if !record.Existed {
+			return removeIfExists(step.target())
+		}
+
+		if _, statError := os.Stat(record.Backup); statError == nil {
+			return replacePath(record.Backup, step.target())
+		}
+	}
+
+	return nil
+}
+
+func removeIfExists(path string) error {
+	if removeError := os.Remove(path); removeError != nil && !os.IsNotExist(removeError) {
+		return removeError
+	}
+
+	return nil
+}
+
+func (step *Step) target() string {
+	if step.Kind == StepExport || step.Kind == StepFork {
+		return step.Destination
+	}
+
+	return step.Path
+}
+
+func backupFile(filePath, backupPath string) (bool, error) {
+	if _, statError := os.Stat(filePath); os.IsNotExist(statError) {
+		return false, nil
+	}
+
+	if linkError := os.Link(filePath, backupPath); linkError == nil {
+		return true, nil
+	}
+
+	source, openError := os.Open(filePath)
+
+	if openError != nil {
+		return false, openError
+	}
+
+	defer func() { _ = source.Close() }()
+
+	destination, createError := os.Create(backupPath)
+
+	if createError != nil {
+		return false, createError
+	}
+
+	defer func() { _ = destination.Close() }()
+
+	if _, copyError := io.Copy(destination, source); copyError != nil {
+		return false, copyError
+	}
+
+	return true, destination.Sync()
+}
+
+func RecoverTransactions() (int, error) {
+	var recoveredCount int
+	var recoverErrors []error
+
+	for _, journalDirectory := range journalDirectories() {
+		journalEntries, readError := os.ReadDir(journalDirectory)
+
+		if readError != nil {
+			if !os.IsNotExist(readError) {
+				recoverErrors = append(recoverErrors, readError)
+			}
+
+			continue
+		}
+
+		for _, journalEntry := range journalEntries {
+			if journalEntry.IsDir() || !strings.HasSuffix(journalEntry.Name(), ".json") {
+				continue
+			}
+
+			fileData, fileError := os.ReadFile(filepath.Join(journalDirectory, journalEntry.Name()))
+
+			if fileError != nil {
+				continue
+			}
+
+			var interruptedTransaction transaction
+
+			if unmarshalError := json.Unmarshal(fileData, &interruptedTransaction); unmarshalError != nil {
+				continue
+			}
+
+			if interruptedTransaction.ProcessID != os.Getpid() && processIsRunning(interruptedTransaction.ProcessID) {
+				continue
+			}
+
+			interruptedTransaction.Journal = journalDirectory
+
+			if interruptedTransaction.Committed {
+				interruptedTransaction.discard()
+			} else if rollbackError := interruptedTransaction.rollback(); rollbackError != nil {
+				recoverErrors = append(recoverErrors, fmt.Errorf("%s: %w", interruptedTransaction.Operation.Description,
+					rollbackError))
+
+				continue
+			}
+
+			recoveredCount += 1
+		}
+	}
+
+	return recoveredCount, errors.Join(recoverErrors...)
+}
@@ -0,0 +1,144 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+type usageCacheEntry struct {
+	modified time.Time
+	size     int64
+	tokens   int
+}
+
+var (
+	usageCache      = map[string]usageCacheEntry{}
+	usageCacheMutex sync.Mutex
+)
+
+func LoadTokenUsage(sessions []Session) {
+	for sessionIndex := range sessions {
+		sessions[sessionIndex].TokenUsage = sessionTokenUsage(sessions[sessionIndex].FullPath)
+	}
+}
+
+func ApplyCachedTokenUsage(sessions []Session) bool {
+	isComplete := true
+
+	for sessionIndex := range sessions {
+		tokens, isCached := cachedTokenUsage(sessions[sessionIndex].FullPath)
+		sessions[sessionIndex].TokenUsage = tokens
+
+		if !isCached {
+			isComplete = false
+		}
+	}
+
+	return isComplete
+}
+
+func cachedTokenUsage(filePath string) (int, bool) {
+	fileInfo, statError := os.Stat(filePath)
+
+	if statError != nil {
+		return 0, true
+	}
+
+	usageCacheMutex.Lock()
+
+	cached, isCached := usageCache[filePath]
+
+	usageCacheMutex.Unlock()
+
+	if isCached && cached.modified.Equal(fileInfo.ModTime()) && cached.size == fileInfo.Size() {
+		return cached.tokens, true
+	}
+
+	return 0, false
+}
+
+func sessionTokenUsage(filePath string) int {
+	if tokens, isCached := cachedTokenUsage(filePath); isCached {
+		return tokens
+	}
+
+	fileInfo, statError := os.Stat(filePath)
+
+	if statError != nil {
+		return 0
+	}
+
+	tokens := countTokenUsage(filePath)
+
+	usageCacheMutex.Lock()
+
+	usageCache[filePath] = usageCacheEntry{modified: fileInfo.ModTime(), size: fileInfo.Size(), tokens: tokens}
+
+	usageCacheMutex.Unlock()
+
+	return tokens
+}
+
+func countTokenUsage(filePath string) int {
+	file, openError := os.Open(filePath)
+
+	if openError != nil {
+		return 0
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanBuffer := make([]byte, 0, 64*1024)
+
+	scanner.Buffer(scanBuffer, 10*1024*1024)
+
+	var tokens int
+
+	countedMessages := map[string]bool{}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			continue
+		}
+
+		var lineData struct {
+			Type    string `json:"type"`
+			Message struct {
+				ID    string `json:"id"`
+				Usage struct {
+					InputTokens              int `json:"input_tokens"`
+					OutputTokens             int `json:"output_tokens"`
+					CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+					CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+				} `json:"usage"`
+			} `json:"message"`
+		}
+
+		if unmarshalError := json.Unmarshal([]byte(line), &lineData); unmarshalError != nil {
+			continue
+		}
+
+		if lineData.Type != "assistant" {
+			continue
+		}
+
+		if lineData.Message.ID != "" {
+			if countedMessages[lineData.Message.ID] {
+				continue
+			}
+
+			countedMessages[lineData.Message.ID] = true
+		}
+
+		usage := lineData.Message.Usage
+		tokens += usage.InputTokens + usage.OutputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
+	}
+
+	return tokens
+}
@@ -0,0 +1,336 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/Fuwn/faustus/internal/claude"
+	"github.com/Fuwn/faustus/internal/ui"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Paths     Paths              `toml:"paths"`
+	Preview   Preview            `toml:"preview"`
+	Interface Interface          `toml:"interface"`
+	Colors    map[string]string  `toml:"colors"`
+	Themes    map[string]Theme   `toml:"themes"`
+	Keys      map[string]KeyList `toml:"keys"`
+	Roots     []Root             `toml:"roots"`
+}
+
+type Paths struct {
+	ClaudeDir     string   `toml:"claude_dir"`
+	TrashDir      string   `toml:"trash_dir"`
+	Label         string   `toml:"label"`
+	RelocateRoots []string `toml:"relocate_roots"`
+}
+
+type Root struct {
+	Label    string `toml:"label"`
+	Dir      string `toml:"dir"`
+	TrashDir string `toml:"trash_dir"`
+}
+
+type Preview struct {
+	Messages int `toml:"messages"`
+	Truncate int `toml:"truncate"`
+}
+
+type Interface struct {
+	Theme          string   `toml:"theme"`
+	StackWidth     int      `toml:"stack_width"`
+	MessageTimeout Duration `toml:"message_timeout"`
+	DryRun         bool     `toml:"dry_run"`
+}
+
+type Theme map[string]string
+
+type KeyList []string
+
+func (keyList *KeyList) UnmarshalTOML(value any) error {
+	switch typedValue := value.(type) {
+	case string:
+		*keyList = KeyList{typedValue}
+
+		return nil
+	case []any:
+		keys := make(KeyList, 0, len(typedValue))
+
+		for _, element := range typedValue {
+			keyName, isString := element.(string)
+
+			if !isString {
+				return fmt.Errorf("%v is not a key name", element)
+			}
+
+			keys = append(keys, keyName)
+		}
+
+		*keyList = keys
+
+		return nil
+	}
+
+	return fmt.Errorf("%v is not a key name or a list of key names", value)
+}
+
+type Duration struct {
+	time.Duration
+}
+
+func (duration *Duration) UnmarshalText(text []byte) error {
+	parsed, parseError := time.ParseDuration(string(text))
+
+	if parseError != nil {
+		return fmt.Errorf("%q is not a duration such as \"3s\" or \"1500ms\"", string(text))
+	}
+
+	duration.Duration = parsed
+
+	return nil
+}
+
+func (duration Duration) MarshalText() ([]byte, error) {
+	return []byte(duration.String()), nil
+}
+
+func Default() Config {
+	return Config{
+		Paths: Paths{
+			ClaudeDir: claude.DefaultClaudeDir(),
+		},
+		Preview: Preview{
+			Messages: 50,
+			Truncate: 500,
+		},
+		Interface: Interface{
+			Theme:          ui.AutoTheme,
+			StackWidth:     100,
+			MessageTimeout: Duration{3 * time.Second},
+		},
+		Colors: map[string]string{},
+		Themes: map[string]Theme{},
+		Keys:   defaultKeys(),
+	}
+}
+
+func defaultKeys() map[string]KeyList {
+	keyMap := ui.DefaultKeyMap()
+	keys := map[string]KeyList{}
+
+	for name, keyNames := range keyMap.Keys() {
+		keys[name] = keyNames
+	}
+
+	return keys
+}
+
+func Path() string {
+	configDirectory, configError := os.UserConfigDir()
+
+	if configError != nil {
+		homeDirectory, _ := os.UserHomeDir()
+		configDirectory = filepath.Join(homeDirectory, ".config")
+	}
+
+	return filepath.Join(configDirectory, "faustus", "config.toml")
+}
+
+func Load(configPath string, required bool) (Config, error) {
+	loadedConfig := Default()
+	metadata, decodeError := toml.DecodeFile(configPath, &loadedConfig)
+
+	if decodeError != nil {
+		if os.IsNotExist(decodeError) && !required {
+			return loadedConfig, nil
+		}
+
+		return loadedConfig, fmt.Errorf("%s: %w", configPath, decodeError)
+	}
+
+	var problems []error
+
+	for _, key := range metadata.Undecoded() {
+		problems = append(problems, fmt.Errorf("%s: unknown setting %q", configPath, key.String()))
+	}
+
+	return loadedConfig, errors.Join(problems...)
+}
+
+func (loadedConfig *Config) Validate() error {
+	var problems []error
+
+	if loadedConfig.Preview.Messages < 1 {
+		problems = append(problems, fmt.Errorf("preview.messages must be at least 1, not %d", loadedConfig.Preview.Messages))
+	}
+
+	if loadedConfig.Preview.Truncate < 1 {
+		problems = append(problems, fmt.Errorf("preview.truncate must be at least 1, not %d", loadedConfig.Preview.Truncate))
+	}
+
+	if loadedConfig.Interface.StackWidth < 0 {
+		problems = append(problems, fmt.Errorf("interface.stack_width must not be negative, not %d",
+			loadedConfig.Interface.StackWidth))
+	}
+
+	if loadedConfig.Interface.MessageTimeout.Duration <= 0 {
+		problems = append(problems, fmt.Errorf("interface.message_timeout must be positive, not %s",
+			loadedConfig.Interface.MessageTimeout))
+	}
+
+	if strings.TrimSpace(loadedConfig.Paths.ClaudeDir) == "" {
+		problems = append(problems, errors.New("paths.claude_dir must not be empty"))
+	}
+
+	for rootIndex, relocateRoot := range loadedConfig.Paths.RelocateRoots {
+		if strings.TrimSpace(relocateRoot) == "" {
+			problems = append(problems, fmt.Errorf("paths.relocate_roots[%d] must not be empty", rootIndex))
+		}
+	}
+
+	for rootIndex, root := range loadedConfig.Roots {
+		if strings.TrimSpace(root.Dir) == "" {
+			problems = append(problems, fmt.Errorf("roots[%d].dir must not be empty", rootIndex))
+		}
+	}
+
+	seenLabels := map[string]bool{}
+	seenDirectories := map[string]bool{}
+
+	for _, root := range loadedConfig.ClaudeRoots() {
+		if seenLabels[root.Label] {
+			problems = append(problems, fmt.Errorf("root label %q is used more than once; set a distinct label", root.Label))
+		}
+
+		if seenDirectories[filepath.Clean(root.Dir)] {
+			problems = append(problems, fmt.Errorf("root directory %s is listed more than once", root.Dir))
+		}
+
+		seenLabels[root.Label] = true
+		seenDirectories[filepath.Clean(root.Dir)] = true
+	}
+
+	palette := ui.Palette()
+
+	for _, name := range sortedKeys(loadedConfig.Colors) {
+		if _, exists := palette[name]; !exists {
+			problems = append(problems, fmt.Errorf("colors.%s is not a palette colour; known colours are %s", name,
+				strings.Join(ui.ColorNames(), ", ")))
+
+			continue
+		}
+
+		if validateError := ui.ValidateColor(loadedConfig.Colors[name]); validateError != nil {
+			problems = append(problems, fmt.Errorf("colors.%s: %w", name, validateError))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(loadedConfig.Themes)) {
+		if name == ui.AutoTheme || slices.Contains(ui.BuiltinThemeNames(), name) {
+			problems = append(problems, fmt.Errorf("themes.%s has the name of a built-in theme; choose another name", name))
+
+			continue
+		}
+
+		if _, themeError := ui.ThemeColors(name, loadedConfig.userThemes()); themeError != nil {
+			problems = append(problems, themeError)
+		}
+	}
+
+	if _, defined := loadedConfig.Themes[loadedConfig.Interface.Theme]; !defined &&
+		loadedConfig.Interface.Theme != ui.AutoTheme && !slices.Contains(ui.BuiltinThemeNames(), loadedConfig.Interface.Theme) {
+		problems = append(problems, fmt.Errorf("interface.theme %q is not a theme; known themes are %s, %s",
+			loadedConfig.Interface.Theme, ui.AutoTheme, strings.Join(ui.ThemeNames(loadedConfig.userThemes()), ", ")))
+	}
+
+	if _, keyError := loadedConfig.KeyMap(); keyError != nil {
+		problems = append(problems, keyError)
+	}
+
+	return errors.Join(problems...)
+}
+
+func (loadedConfig *Config) Palette() (map[string]string, error) {
+	palette, themeError := ui.ThemeColors(loadedConfig.Interface.Theme, loadedConfig.userThemes())
+
+	if themeError != nil {
+		return nil, themeError
+	}
+
+	maps.Copy(palette, loadedConfig.Colors)
+
+	return palette, nil
+}
+
+func (loadedConfig *Config) userThemes() map[string]map[string]string {
+	themes := map[string]map[string]string{}
+
+	for name, theme := range loadedConfig.Themes {
+		themes[name] = theme
+	}
+
+	return themes
+}
+
+func (loadedConfig *Config) KeyMap() (ui.KeyMap, error) {
+	keyMap := ui.DefaultKeyMap()
+	overrides := map[string][]string{}
+
+	for name, keyNames := range loadedConfig.Keys {
+		overrides[name] = keyNames
+	}
+
+	overrideError := keyMap.Override(overrides)
+
+	return keyMap, overrideError
+}
+
+func (loadedConfig *Config) ClaudeRoots() []claude.Root {
+	roots := []claude.Root{claude.NewRoot(loadedConfig.Paths.Label, ExpandHome(loadedConfig.Paths.ClaudeDir),
+		ExpandHome(loadedConfig.Paths.TrashDir))}
+
+	for _, root := range loadedConfig.Roots {
+		roots = append(roots, claude.NewRoot(root.Label, ExpandHome(root.Dir), ExpandHome(root.TrashDir)))
+	}
+
+	return roots
+}
+
+func (loadedConfig *Config) RelocateRoots() []string {
+	var relocateRoots []string
+
+	for _, relocateRoot := range loadedConfig.Paths.RelocateRoots {
+		relocateRoots = append(relocateRoots, ExpandHome(relocateRoot))
+	}
+
+	return relocateRoots
+}
+
+func (loadedConfig *Config) Encode(writer io.Writer) error {
+	return toml.NewEncoder(writer).Encode(loadedConfig)
+}
+
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	homeDirectory, homeError := os.UserHomeDir()
+
+	if homeError != nil {
+		return path
+	}
+
+	return filepath.Join(homeDirectory, strings.TrimPrefix(path, "~"))
+}
+
+func sortedKeys(values map[string]string) []string {
+	return slices.Sorted(maps.Keys(values))
+}
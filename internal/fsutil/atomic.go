@@ -0,0 +1,74 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var (
+	writeFile  = (*os.File).Write
+	syncFile   = (*os.File).Sync
+	renameFile = os.Rename
+)
+
+func WriteFileAtomic(filePath string, data []byte, permissions os.FileMode) error {
+	directory := filepath.Dir(filePath)
+
+	if fileInfo, statError := os.Stat(filePath); statError == nil {
+		permissions = fileInfo.Mode().Perm()
+	}
+
+	temporaryFile, createError := os.CreateTemp(directory, "."+filepath.Base(filePath)+".*.tmp")
+
+	if createError != nil {
+		return createError
+	}
+
+	temporaryPath := temporaryFile.Name()
+	isCommitted := false
+
+	defer func() {
+		if !isCommitted {
+			_ = temporaryFile.Close()
+			_ = os.Remove(temporaryPath)
+		}
+	}()
+
+	if _, writeError := writeFile(temporaryFile, data); writeError != nil {
+		return writeError
+	}
+
+	if chmodError := temporaryFile.Chmod(permissions); chmodError != nil {
+		return chmodError
+	}
+
+	if syncError := syncFile(temporaryFile); syncError != nil {
+		return syncError
+	}
+
+	if closeError := temporaryFile.Close(); closeError != nil {
+		return closeError
+	}
+
+	if renameError := renameFile(temporaryPath, filePath); renameError != nil {
+		return renameError
+	}
+
+	isCommitted = true
+
+	syncDirectory(directory)
+
+	return nil
+}
+
+func syncDirectory(directory string) {
+	directoryFile, openError := os.Open(directory)
+
+	if openError != nil {
+		return
+	}
+
+	defer func() { _ = directoryFile.Close() }()
+
+	_ = directoryFile.Sync()
+}
@@ -0,0 +1,132 @@
+package fsutil
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var errSimulatedFailure = errors.New("simulated failure")
+
+func failRenames(t *testing.T) {
+	t.Helper()
+
+	originalRename := renameFile
+	renameFile = func(string, string) error { return errSimulatedFailure }
+
+	t.Cleanup(func() { renameFile = originalRename })
+}
+
+func failWrites(t *testing.T) {
+	t.Helper()
+
+	originalWrite := writeFile
+	writeFile = func(file *os.File, data []byte) (int, error) {
+		writtenCount, _ := file.Write(data[:len(data)/2])
+
+		return writtenCount, errSimulatedFailure
+	}
+
+	t.Cleanup(func() { writeFile = originalWrite })
+}
+
+func failSyncs(t *testing.T) {
+	t.Helper()
+
+	originalSync := syncFile
+	syncFile = func(*os.File) error { return errSimulatedFailure }
+
+	t.Cleanup(func() { syncFile = originalSync })
+}
+
+func assertOriginalKept(t *testing.T, filePath string, originalData []byte) {
+	t.Helper()
+
+	fileData, readError := os.ReadFile(filePath)
+
+	if readError != nil {
+		t.Fatal(readError)
+	}
+
+	if !bytes.Equal(fileData, originalData) {
+		t.Fatalf("original file changed to %q", fileData)
+	}
+
+	entries, readError := os.ReadDir(filepath.Dir(filePath))
+
+	if readError != nil {
+		t.Fatal(readError)
+	}
+
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp") {
+			t.Fatalf("temporary file %s was left behind", entry.Name())
+		}
+	}
+}
+
+func writeOriginal(t *testing.T, permissions os.FileMode) (string, []byte) {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "sessions-index.json")
+	originalData := []byte(`{"version":1,"entries":[]}`)
+
+	if writeError := os.WriteFile(filePath, originalData, permissions); writeError != nil {
+		t.Fatal(writeError)
+	}
+
+	if chmodError := os.Chmod(filePath, permissions); chmodError != nil {
+		t.Fatal(chmodError)
+	}
+
+	return filePath, originalData
+}
+
+func TestWriteFileAtomicFailureKeepsOriginal(t *testing.T) {
+	failures := map[string]func(*testing.T){
+		"write":  failWrites,
+		"sync":   failSyncs,
+		"rename": failRenames,
+	}
+
+	for name, inject := range failures {
+		t.Run(name, func(t *testing.T) {
+			filePath, originalData := writeOriginal(t, 0o644)
+
+			inject(t)
+
+			if writeError := WriteFileAtomic(filePath, []byte(`{"version":1,"entries":[{}]}`), 0o644); !errors.Is(writeError, errSimulatedFailure) {
+				t.Fatalf("expected the simulated %s failure, got %v", name, writeError)
+			}
+
+			assertOriginalKept(t, filePath, originalData)
+		})
+	}
+}
+
+func TestWriteFileAtomicKeepsPermissions(t *testing.T) {
+	filePath, _ := writeOriginal(t, 0o600)
+
+	if writeError := WriteFileAtomic(filePath, []byte("replacement"), 0o644); writeError != nil {
+		t.Fatal(writeError)
+	}
+
+	fileInfo, statError := os.Stat(filePath)
+
+	if statError != nil {
+		t.Fatal(statError)
+	}
+
+	if permissions := fileInfo.Mode().Perm(); permissions != 0o600 {
+		t.Fatalf("expected permissions 0600, got %#o", permissions)
+	}
+
+	fileData, _ := os.ReadFile(filePath)
+
+	if string(fileData) != "replacement" {
+		t.Fatalf("expected the new contents, got %q", fileData)
+	}
+}
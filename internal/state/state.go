@@ -0,0 +1,70 @@
+package state
+
+import (
+	"encoding/json"
+	"github.com/Fuwn/faustus/internal/fsutil"
+	"os"
+	"path/filepath"
+)
+
+type State struct {
+	SortField      string  `json:"sortField"`
+	SortDescending bool    `json:"sortDescending"`
+	GroupByProject bool    `json:"groupByProject"`
+	ProjectNames   string  `json:"projectNames"`
+	ShowTags       bool    `json:"showTags"`
+	Layout         string  `json:"layout"`
+	SplitRatio     float64 `json:"splitRatio"`
+}
+
+func Default() State {
+	return State{
+		SortField:      "modified",
+		SortDescending: true,
+		ProjectNames:   "short",
+		Layout:         "auto",
+		SplitRatio:     0.5,
+	}
+}
+
+func Path() string {
+	configDirectory, configError := os.UserConfigDir()
+
+	if configError != nil {
+		homeDirectory, _ := os.UserHomeDir()
+		configDirectory = filepath.Join(homeDirectory, ".config")
+	}
+
+	return filepath.Join(configDirectory, "faustus", "state.json")
+}
+
+func Load() State {
+	loadedState := Default()
+	fileData, readError := os.ReadFile(Path())
+
+	if readError != nil {
+		return loadedState
+	}
+
+	if unmarshalError := json.Unmarshal(fileData, &loadedState); unmarshalError != nil {
+		return Default()
+	}
+
+	return loadedState
+}
+
+func Save(savedState State) error {
+	statePath := Path()
+
+	if mkdirError := os.MkdirAll(filepath.Dir(statePath), 0o755); mkdirError != nil {
+		return mkdirError
+	}
+
+	jsonData, marshalError := json.MarshalIndent(savedState, "", "  ")
+
+	if marshalError != nil {
+		return marshalError
+	}
+
+	return fsutil.WriteFileAtomic(statePath, jsonData, 0o644)
+}
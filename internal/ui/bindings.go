@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type keyScope struct {
+	name     string
+	bindings []string
+}
+
+type HelpEntry struct {
+	Key         string
+	Description string
+}
+
+var listBindings = []string{
+	"up", "down", "left", "right", "top", "bottom", "half_up", "half_down", "search", "deep_search",
+	"next_match", "prev_match", "preview", "layout", "zoom", "grow_list", "shrink_list", "tab", "sort",
+	"sort_order", "group", "project_name", "collapse",
+	"export", "export_html", "delete", "restore", "rename", "auto_title", "pin", "note", "tag", "tag_sidebar",
+	"reassign", "reassign_all", "move_root", "copy_root", "relocate", "dry_run", "doctor", "reindex", "clear",
+	"fork", "palette", "help", "quit",
+}
+
+var keyScopes = []keyScope{
+	{name: "the session list", bindings: listBindings},
+	{name: "text prompts", bindings: []string{"escape", "enter", "tab"}},
+	{name: "the note editor", bindings: []string{"escape", "save_note"}},
+	{name: "confirmations", bindings: []string{"escape", "deny", "confirm", "include_pinned"}},
+	{name: "the plan preview", bindings: []string{"escape", "deny", "up", "down", "confirm"}},
+	{name: "the doctor report", bindings: []string{"escape", "quit", "up", "down", "fix"}},
+	{name: "the relocation list", bindings: []string{"escape", "up", "down", "collapse", "confirm"}},
+	{name: "the root picker", bindings: []string{"escape", "up", "down", "enter"}},
+	{name: "the command palette", bindings: []string{"escape", "enter", "palette_up", "palette_down"}},
+}
+
+func (keyMap *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":             &keyMap.Up,
+		"down":           &keyMap.Down,
+		"left":           &keyMap.Left,
+		"right":          &keyMap.Right,
+		"enter":          &keyMap.Enter,
+		"delete":         &keyMap.Delete,
+		"restore":        &keyMap.Restore,
+		"rename":         &keyMap.Rename,
+		"reassign":       &keyMap.Reassign,
+		"reassign_all":   &keyMap.ReassignAll,
+		"search":         &keyMap.Search,
+		"deep_search":    &keyMap.DeepSearch,
+		"next_match":     &keyMap.NextMatch,
+		"prev_match":     &keyMap.PrevMatch,
+		"tab":            &keyMap.Tab,
+		"clear":          &keyMap.Clear,
+		"quit":           &keyMap.Quit,
+		"help":           &keyMap.Help,
+		"escape":         &keyMap.Escape,
+		"confirm":        &keyMap.Confirm,
+		"half_up":        &keyMap.HalfUp,
+		"half_down":      &keyMap.HalfDown,
+		"top":            &keyMap.Top,
+		"bottom":         &keyMap.Bottom,
+		"preview":        &keyMap.Preview,
+		"sort":           &keyMap.Sort,
+		"sort_order":     &keyMap.SortOrder,
+		"group":          &keyMap.Group,
+		"collapse":       &keyMap.Collapse,
+		"export":         &keyMap.Export,
+		"export_html":    &keyMap.ExportHTML,
+		"project_name":   &keyMap.ProjectName,
+		"relocate":       &keyMap.Relocate,
+		"dry_run":        &keyMap.DryRun,
+		"doctor":         &keyMap.Doctor,
+		"reindex":        &keyMap.Reindex,
+		"auto_title":     &keyMap.AutoTitle,
+		"tag":            &keyMap.Tag,
+		"tag_sidebar":    &keyMap.TagSidebar,
+		"pin":            &keyMap.Pin,
+		"note":           &keyMap.Note,
+		"move_root":      &keyMap.MoveRoot,
+		"copy_root":      &keyMap.CopyRoot,
+		"fork":           &keyMap.Fork,
+		"deny":           &keyMap.Deny,
+		"include_pinned": &keyMap.IncludePinned,
+		"fix":            &keyMap.Fix,
+		"save_note":      &keyMap.SaveNote,
+		"palette":        &keyMap.Palette,
+		"palette_up":     &keyMap.PaletteUp,
+		"palette_down":   &keyMap.PaletteDown,
+		"layout":         &keyMap.Layout,
+		"zoom":           &keyMap.Zoom,
+		"grow_list":      &keyMap.GrowList,
+		"shrink_list":    &keyMap.ShrinkList,
+	}
+}
+
+func BindingNames() []string {
+	keyMap := DefaultKeyMap()
+	names := make([]string, 0, len(keyMap.bindings()))
+
+	for name := range keyMap.bindings() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func ListBindingNames() []string {
+	return slices.Clone(listBindings)
+}
+
+func (keyMap *KeyMap) Binding(name string) (key.Binding, bool) {
+	binding, exists := keyMap.bindings()[name]
+
+	if !exists {
+		return key.Binding{}, false
+	}
+
+	return *binding, true
+}
+
+func (keyMap *KeyMap) Keys() map[string][]string {
+	keys := map[string][]string{}
+
+	for name, binding := range keyMap.bindings() {
+		keys[name] = binding.Keys()
+	}
+
+	return keys
+}
+
+func (keyMap *KeyMap) Override(overrides map[string][]string) error {
+	var problems []error
+
+	bindings := keyMap.bindings()
+	names := make([]string, 0, len(overrides))
+
+	for name := range overrides {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		binding, exists := bindings[name]
+
+		if !exists {
+			problems = append(problems, fmt.Errorf("keys.%s is not a binding; known bindings are %s", name,
+				strings.Join(BindingNames(), ", ")))
+
+			continue
+		}
+
+		var keys []string
+
+		for _, keyName := range overrides[name] {
+			if keyName == "space" {
+				keyName = " "
+			}
+
+			if keyName != "" && !slices.Contains(keys, keyName) {
+				keys = append(keys, keyName)
+			}
+		}
+
+		if len(keys) == 0 {
+			problems = append(problems, fmt.Errorf("keys.%s needs at least one key", name))
+
+			continue
+		}
+
+		if slices.Equal(keys, binding.Keys()) {
+			continue
+		}
+
+		*binding = key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyLabel(keys[0]), binding.Help().Desc))
+	}
+
+	return errors.Join(append(problems, keyMap.conflicts()...)...)
+}
+
+func (keyMap *KeyMap) conflicts() []error {
+	var problems []error
+
+	bindings := keyMap.bindings()
+
+	for _, scope := range keyScopes {
+		owners := map[string]string{}
+
+		for _, name := range scope.bindings {
+			for _, keyName := range bindings[name].Keys() {
+				if owner, taken := owners[keyName]; taken && owner != name {
+					problems = append(problems, fmt.Errorf("key %q is bound to both %s and %s in %s", keyLabel(keyName),
+						owner, name, scope.name))
+
+					continue
+				}
+
+				owners[keyName] = name
+			}
+		}
+	}
+
+	return problems
+}
+
+func (keyMap *KeyMap) HelpEntries() []HelpEntry {
+	bindings := keyMap.bindings()
+	entries := make([]HelpEntry, 0, len(listBindings))
+
+	for _, name := range listBindings {
+		help := bindings[name].Help()
+
+		entries = append(entries, HelpEntry{Key: help.Key, Description: help.Desc})
+	}
+
+	return entries
+}
+
+func keyLabel(keyName string) string {
+	if keyName == " " {
+		return "space"
+	}
+
+	return keyName
+}
@@ -3,31 +3,61 @@ package ui
 import "github.com/charmbracelet/bubbles/key"
 
 type KeyMap struct {
-	Up          key.Binding
-	Down        key.Binding
-	Left        key.Binding
-	Right       key.Binding
-	Enter       key.Binding
-	Delete      key.Binding
-	Restore     key.Binding
-	Rename      key.Binding
-	Reassign    key.Binding
-	ReassignAll key.Binding
-	Search      key.Binding
-	DeepSearch  key.Binding
-	NextMatch   key.Binding
-	PrevMatch   key.Binding
-	Tab         key.Binding
-	Clear       key.Binding
-	Quit        key.Binding
-	Help        key.Binding
-	Escape      key.Binding
-	Confirm     key.Binding
-	HalfUp      key.Binding
-	HalfDown    key.Binding
-	Top         key.Binding
-	Bottom      key.Binding
-	Preview     key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	Left          key.Binding
+	Right         key.Binding
+	Enter         key.Binding
+	Delete        key.Binding
+	Restore       key.Binding
+	Rename        key.Binding
+	Reassign      key.Binding
+	ReassignAll   key.Binding
+	Search        key.Binding
+	DeepSearch    key.Binding
+	NextMatch     key.Binding
+	PrevMatch     key.Binding
+	Tab           key.Binding
+	Clear         key.Binding
+	Quit          key.Binding
+	Help          key.Binding
+	Escape        key.Binding
+	Confirm       key.Binding
+	HalfUp        key.Binding
+	HalfDown      key.Binding
+	Top           key.Binding
+	Bottom        key.Binding
+	Preview       key.Binding
+	Sort          key.Binding
+	SortOrder     key.Binding
+	Group         key.Binding
+	Collapse      key.Binding
+	Export        key.Binding
+	ExportHTML    key.Binding
+	ProjectName   key.Binding
+	Relocate      key.Binding
+	DryRun        key.Binding
+	Doctor        key.Binding
+	Reindex       key.Binding
+	AutoTitle     key.Binding
+	Tag           key.Binding
+	TagSidebar    key.Binding
+	Pin           key.Binding
+	Note          key.Binding
+	MoveRoot      key.Binding
+	CopyRoot      key.Binding
+	Fork          key.Binding
+	Deny          key.Binding
+	IncludePinned key.Binding
+	Fix           key.Binding
+	SaveNote      key.Binding
+	Palette       key.Binding
+	PaletteUp     key.Binding
+	PaletteDown   key.Binding
+	Layout        key.Binding
+	Zoom          key.Binding
+	GrowList      key.Binding
+	ShrinkList    key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -74,11 +104,11 @@ func DefaultKeyMap() KeyMap {
 		),
 		Restore: key.NewBinding(
 			key.WithKeys("u"),
-			key.WithHelp("u", "restore"),
+			key.WithHelp("u", "restore from bin"),
 		),
 		Rename: key.NewBinding(
 			key.WithKeys("c"),
-			key.WithHelp("c", "rename"),
+			key.WithHelp("c", "rename session"),
 		),
 		Reassign: key.NewBinding(
 			key.WithKeys("r"),
@@ -86,15 +116,15 @@ func DefaultKeyMap() KeyMap {
 		),
 		ReassignAll: key.NewBinding(
 			key.WithKeys("R"),
-			key.WithHelp("R", "reassign all"),
+			key.WithHelp("R", "reassign all with folder"),
 		),
 		Search: key.NewBinding(
 			key.WithKeys("/"),
-			key.WithHelp("/", "filter"),
+			key.WithHelp("/", "filter sessions"),
 		),
 		DeepSearch: key.NewBinding(
 			key.WithKeys("s"),
-			key.WithHelp("s", "search"),
+			key.WithHelp("s", "search all sessions"),
 		),
 		NextMatch: key.NewBinding(
 			key.WithKeys("n"),
@@ -118,7 +148,7 @@ func DefaultKeyMap() KeyMap {
 		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
+			key.WithHelp("?", "toggle help"),
 		),
 		Escape: key.NewBinding(
 			key.WithKeys("esc"),
@@ -132,5 +162,125 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("p"),
 			key.WithHelp("p", "toggle preview"),
 		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "cycle sort field"),
+		),
+		SortOrder: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "reverse sort order"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "group by project"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("enter", " "),
+			key.WithHelp("space", "collapse or expand project"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "export as markdown"),
+		),
+		ExportHTML: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "export as html"),
+		),
+		ProjectName: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "cycle project name style"),
+		),
+		Relocate: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "relocate missing project folders"),
+		),
+		DryRun: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "toggle dry run"),
+		),
+		Doctor: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "check session storage"),
+		),
+		Reindex: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "rebuild project index"),
+		),
+		AutoTitle: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "generate titles"),
+		),
+		Tag: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "edit tags"),
+		),
+		TagSidebar: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "toggle tag sidebar"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "pin or unpin session"),
+		),
+		Note: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "edit note"),
+		),
+		MoveRoot: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "move to another root"),
+		),
+		CopyRoot: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "copy to another root"),
+		),
+		Fork: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "fork session"),
+		),
+		Deny: key.NewBinding(
+			key.WithKeys("n", "N"),
+			key.WithHelp("n", "cancel"),
+		),
+		IncludePinned: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "include pinned"),
+		),
+		Fix: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "fix"),
+		),
+		SaveNote: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "save"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys(":", "ctrl+p"),
+			key.WithHelp(":", "command palette"),
+		),
+		PaletteUp: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("up", "previous command"),
+		),
+		PaletteDown: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("down", "next command"),
+		),
+		Layout: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "cycle preview layout"),
+		),
+		Zoom: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "zoom focused pane"),
+		),
+		GrowList: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "enlarge list pane"),
+		),
+		ShrinkList: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "shrink list pane"),
+		),
 	}
 }
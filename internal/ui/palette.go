@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func paletteColors() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"primary":       &Primary,
+		"secondary":     &Secondary,
+		"tertiary":      &Tertiary,
+		"accent":        &Accent,
+		"bg_base":       &BgBase,
+		"bg_lighter":    &BgLighter,
+		"bg_subtle":     &BgSubtle,
+		"bg_overlay":    &BgOverlay,
+		"fg_base":       &FgBase,
+		"fg_muted":      &FgMuted,
+		"fg_half_muted": &FgHalfMute,
+		"fg_subtle":     &FgSubtle,
+		"fg_bright":     &FgBright,
+		"success":       &Success,
+		"error":         &Error,
+		"warning":       &Warning,
+		"info":          &Info,
+		"blue":          &Blue,
+		"green":         &Green,
+		"green_dark":    &GreenDark,
+		"red":           &Red,
+		"red_dark":      &RedDark,
+		"yellow":        &Yellow,
+		"orange":        &Orange,
+		"purple":        &Purple,
+		"cyan":          &Cyan,
+		"pink":          &Pink,
+	}
+}
+
+func ColorNames() []string {
+	colors := paletteColors()
+	names := make([]string, 0, len(colors))
+
+	for name := range colors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func Palette() map[string]string {
+	palette := map[string]string{}
+
+	for name, color := range paletteColors() {
+		palette[name] = string(*color)
+	}
+
+	return palette
+}
+
+func ValidateColor(value string) error {
+	if value == "" || hexColorPattern.MatchString(value) {
+		return nil
+	}
+
+	if number, parseError := strconv.Atoi(value); parseError == nil && number >= 0 && number <= 255 {
+		return nil
+	}
+
+	return fmt.Errorf("%q is not a colour; use #RGB, #RRGGBB, an ANSI number from 0 to 255, or \"\" for the terminal default", value)
+}
+
+func SetColors(colors map[string]string) error {
+	palette := paletteColors()
+
+	for name, value := range colors {
+		if _, exists := palette[name]; !exists {
+			return fmt.Errorf("unknown colour %q", name)
+		}
+
+		if validateError := ValidateColor(value); validateError != nil {
+			return fmt.Errorf("colour %s: %w", name, validateError)
+		}
+	}
+
+	for name, value := range colors {
+		if Monochrome() {
+			value = ""
+		}
+
+		*palette[name] = lipgloss.Color(value)
+	}
+
+	buildStyles()
+
+	return nil
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"hash/fnv"
+)
 
 var (
 	Primary    = lipgloss.Color("#6B50FF")
@@ -30,130 +34,247 @@ var (
 	Purple     = lipgloss.Color("#8B75FF")
 	Cyan       = lipgloss.Color("#0ADCD9")
 	Pink       = lipgloss.Color("#FF60FF")
-	BaseStyle  = lipgloss.NewStyle().
-			Foreground(FgBase)
+)
+
+var (
+	BaseStyle             lipgloss.Style
+	MutedStyle            lipgloss.Style
+	SubtleStyle           lipgloss.Style
+	HeaderStyle           lipgloss.Style
+	LogoStyle             lipgloss.Style
+	TabStyle              lipgloss.Style
+	ActiveTabStyle        lipgloss.Style
+	ItemStyle             lipgloss.Style
+	SelectedItemStyle     lipgloss.Style
+	CursorStyle           lipgloss.Style
+	TitleStyle            lipgloss.Style
+	SummaryStyle          lipgloss.Style
+	MetaStyle             lipgloss.Style
+	ProjectStyle          lipgloss.Style
+	TrashStyle            lipgloss.Style
+	ActiveStyle           lipgloss.Style
+	SearchStyle           lipgloss.Style
+	SearchInputStyle      lipgloss.Style
+	HelpStyle             lipgloss.Style
+	HelpKeyStyle          lipgloss.Style
+	ModalStyle            lipgloss.Style
+	ConfirmStyle          lipgloss.Style
+	StatusBarStyle        lipgloss.Style
+	CountStyle            lipgloss.Style
+	PreviewStyle          lipgloss.Style
+	PreviewFocusedStyle   lipgloss.Style
+	ListBoxStyle          lipgloss.Style
+	ListBoxFocusedStyle   lipgloss.Style
+	PreviewHeaderStyle    lipgloss.Style
+	PreviewDividerStyle   lipgloss.Style
+	UserRoleStyle         lipgloss.Style
+	UserContentStyle      lipgloss.Style
+	AssistantRoleStyle    lipgloss.Style
+	AssistantContentStyle lipgloss.Style
+	ToolRoleStyle         lipgloss.Style
+	ToolContentStyle      lipgloss.Style
+	ThinkingRoleStyle     lipgloss.Style
+	ThinkingContentStyle  lipgloss.Style
+	HighlightStyle        lipgloss.Style
+	MatchStyle            lipgloss.Style
+	SearchResultStyle     lipgloss.Style
+	SearchMatchStyle      lipgloss.Style
+	SearchContextStyle    lipgloss.Style
+	BranchGoneStyle       lipgloss.Style
+	BranchMergedStyle     lipgloss.Style
+	CommitHashStyle       lipgloss.Style
+	MissingStyle          lipgloss.Style
+	SuccessStyle          lipgloss.Style
+	WarningStyle          lipgloss.Style
+	PinnedStyle           lipgloss.Style
+	NoteStyle             lipgloss.Style
+	RootStyle             lipgloss.Style
+	TagColors             []lipgloss.Color
+)
+
+func init() {
+	if Monochrome() {
+		lipgloss.SetColorProfile(termenv.ANSI)
+
+		for _, color := range paletteColors() {
+			*color = ""
+		}
+	}
+
+	buildStyles()
+}
+
+func buildStyles() {
+	BaseStyle = lipgloss.NewStyle().
+		Foreground(FgBase)
 	MutedStyle = lipgloss.NewStyle().
-			Foreground(FgMuted)
+		Foreground(FgMuted)
 	SubtleStyle = lipgloss.NewStyle().
-			Foreground(FgSubtle)
+		Foreground(FgSubtle)
 	HeaderStyle = lipgloss.NewStyle().
-			Foreground(Primary).
-			Bold(true)
+		Foreground(Primary).
+		Bold(true)
 	LogoStyle = lipgloss.NewStyle().
-			Foreground(Secondary).
-			Bold(true)
+		Foreground(Secondary).
+		Bold(true)
 	TabStyle = lipgloss.NewStyle().
-			Foreground(FgMuted).
-			Padding(0, 2)
+		Foreground(FgMuted).
+		Padding(0, 2)
 	ActiveTabStyle = lipgloss.NewStyle().
-			Foreground(Primary).
-			Bold(true).
-			Padding(0, 2)
+		Foreground(Primary).
+		Bold(true).
+		Padding(0, 2)
 	ItemStyle = lipgloss.NewStyle().
-			Foreground(FgBase).
-			PaddingLeft(2)
+		Foreground(FgBase).
+		PaddingLeft(2)
 	SelectedItemStyle = lipgloss.NewStyle().
-				Foreground(FgBright).
-				Background(BgSubtle).
-				Bold(true).
-				PaddingLeft(1).
-				PaddingRight(1)
+		Foreground(FgBright).
+		Background(BgSubtle).
+		Bold(true).
+		PaddingLeft(1).
+		PaddingRight(1)
 	CursorStyle = lipgloss.NewStyle().
-			Foreground(Tertiary).
-			Bold(true)
+		Foreground(Tertiary).
+		Bold(true)
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(FgBase).
-			Bold(true)
+		Foreground(FgBase).
+		Bold(true)
 	SummaryStyle = lipgloss.NewStyle().
-			Foreground(FgHalfMute).
-			Italic(true)
+		Foreground(FgHalfMute).
+		Italic(true)
 	MetaStyle = lipgloss.NewStyle().
-			Foreground(FgSubtle)
+		Foreground(FgSubtle)
 	ProjectStyle = lipgloss.NewStyle().
-			Foreground(Tertiary)
+		Foreground(Tertiary)
 	TrashStyle = lipgloss.NewStyle().
-			Foreground(Error).
-			Bold(true)
+		Foreground(Error).
+		Bold(true)
 	ActiveStyle = lipgloss.NewStyle().
-			Foreground(Success)
+		Foreground(Success)
 	SearchStyle = lipgloss.NewStyle().
-			Foreground(Accent).
-			Bold(true)
+		Foreground(Accent).
+		Bold(true)
 	SearchInputStyle = lipgloss.NewStyle().
-				BorderStyle(lipgloss.RoundedBorder()).
-				BorderForeground(Primary).
-				Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Primary).
+		Padding(0, 1)
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(FgSubtle)
+		Foreground(FgSubtle)
 	HelpKeyStyle = lipgloss.NewStyle().
-			Foreground(FgMuted)
+		Foreground(FgMuted)
 	ModalStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(Primary).
-			Background(BgLighter).
-			Padding(1, 2)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Primary).
+		Background(BgLighter).
+		Padding(1, 2)
 	ConfirmStyle = lipgloss.NewStyle().
-			Foreground(Warning).
-			Bold(true)
+		Foreground(Warning).
+		Bold(true)
 	StatusBarStyle = lipgloss.NewStyle().
-			Foreground(FgBase).
-			Background(BgSubtle).
-			Padding(0, 1)
+		Foreground(FgBase).
+		Background(BgSubtle).
+		Padding(0, 1)
 	CountStyle = lipgloss.NewStyle().
-			Foreground(Tertiary).
-			Bold(true)
+		Foreground(Tertiary).
+		Bold(true)
 	PreviewStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(FgSubtle).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(FgSubtle).
+		Padding(0, 1)
 	PreviewFocusedStyle = lipgloss.NewStyle().
-				BorderStyle(lipgloss.RoundedBorder()).
-				BorderForeground(Primary).
-				Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Primary).
+		Padding(0, 1)
 	ListBoxStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(FgSubtle).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(FgSubtle).
+		Padding(0, 1)
 	ListBoxFocusedStyle = lipgloss.NewStyle().
-				BorderStyle(lipgloss.RoundedBorder()).
-				BorderForeground(Primary).
-				Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(Primary).
+		Padding(0, 1)
 	PreviewHeaderStyle = lipgloss.NewStyle().
-				Foreground(Primary).
-				Bold(true)
+		Foreground(Primary).
+		Bold(true)
 	PreviewDividerStyle = lipgloss.NewStyle().
-				Foreground(FgSubtle)
+		Foreground(FgSubtle)
 	UserRoleStyle = lipgloss.NewStyle().
-			Foreground(Blue).
-			Bold(true)
+		Foreground(Blue).
+		Bold(true)
 	UserContentStyle = lipgloss.NewStyle().
-				Foreground(FgBase)
+		Foreground(FgBase)
 	AssistantRoleStyle = lipgloss.NewStyle().
-				Foreground(GreenDark).
-				Bold(true)
+		Foreground(GreenDark).
+		Bold(true)
 	AssistantContentStyle = lipgloss.NewStyle().
-				Foreground(FgHalfMute)
+		Foreground(FgHalfMute)
 	ToolRoleStyle = lipgloss.NewStyle().
-			Foreground(Orange).
-			Bold(true)
+		Foreground(Orange).
+		Bold(true)
 	ToolContentStyle = lipgloss.NewStyle().
-				Foreground(FgMuted).
-				Italic(true)
+		Foreground(FgMuted).
+		Italic(true)
 	ThinkingRoleStyle = lipgloss.NewStyle().
-				Foreground(Purple).
-				Bold(true)
+		Foreground(Purple).
+		Bold(true)
 	ThinkingContentStyle = lipgloss.NewStyle().
-				Foreground(FgSubtle).
-				Italic(true)
+		Foreground(FgSubtle).
+		Italic(true)
 	HighlightStyle = lipgloss.NewStyle().
-			Background(Accent).
-			Foreground(BgBase).
-			Bold(true)
+		Background(Accent).
+		Foreground(BgBase).
+		Bold(true)
+	MatchStyle = lipgloss.NewStyle().
+		Background(Yellow).
+		Foreground(BgBase)
 	SearchResultStyle = lipgloss.NewStyle().
-				Foreground(FgBase)
+		Foreground(FgBase)
 	SearchMatchStyle = lipgloss.NewStyle().
-				Foreground(Accent).
-				Bold(true)
+		Foreground(Accent).
+		Bold(true)
 	SearchContextStyle = lipgloss.NewStyle().
-				Foreground(FgHalfMute)
-)
+		Foreground(FgHalfMute)
+	BranchGoneStyle = lipgloss.NewStyle().
+		Foreground(Red).
+		Bold(true)
+	BranchMergedStyle = lipgloss.NewStyle().
+		Foreground(Purple).
+		Bold(true)
+	CommitHashStyle = lipgloss.NewStyle().
+		Foreground(Orange)
+	MissingStyle = lipgloss.NewStyle().
+		Foreground(Orange).
+		Bold(true)
+	SuccessStyle = lipgloss.NewStyle().
+		Foreground(Success)
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(Warning)
+	PinnedStyle = lipgloss.NewStyle().
+		Foreground(Accent)
+	NoteStyle = lipgloss.NewStyle().
+		Foreground(Accent).
+		Italic(true)
+	RootStyle = lipgloss.NewStyle().
+		Foreground(Cyan).
+		Bold(true)
+	TagColors = []lipgloss.Color{Blue, Green, Orange, Purple, Cyan, Pink, Yellow, Red}
+
+	if Monochrome() {
+		SelectedItemStyle = SelectedItemStyle.Reverse(true)
+		HighlightStyle = HighlightStyle.Reverse(true)
+		MatchStyle = MatchStyle.Reverse(true)
+		StatusBarStyle = StatusBarStyle.Reverse(true)
+	}
+}
+
+func TagStyle(tag string) lipgloss.Style {
+	hash := fnv.New32a()
+
+	_, _ = hash.Write([]byte(tag))
+
+	return lipgloss.NewStyle().
+		Foreground(BgBase).
+		Background(TagColors[hash.Sum32()%uint32(len(TagColors))]).
+		Reverse(Monochrome()).
+		Padding(0, 1)
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const AutoTheme = "auto"
+
+var builtinThemes = map[string]map[string]string{
+	"dark": {
+		"primary":       "#6B50FF",
+		"secondary":     "#FF60FF",
+		"tertiary":      "#68FFD6",
+		"accent":        "#E8FE96",
+		"bg_base":       "#201F26",
+		"bg_lighter":    "#2D2C35",
+		"bg_subtle":     "#3A3943",
+		"bg_overlay":    "#4D4C57",
+		"fg_base":       "#DFDBDD",
+		"fg_muted":      "#858392",
+		"fg_half_muted": "#BFBCC8",
+		"fg_subtle":     "#605F6B",
+		"fg_bright":     "#F1EFEF",
+		"success":       "#00FFB2",
+		"error":         "#EB4268",
+		"warning":       "#E8FE96",
+		"info":          "#00A4FF",
+		"blue":          "#00A4FF",
+		"green":         "#00FFB2",
+		"green_dark":    "#12C78F",
+		"red":           "#FF577D",
+		"red_dark":      "#EB4268",
+		"yellow":        "#E8FE96",
+		"orange":        "#FF985A",
+		"purple":        "#8B75FF",
+		"cyan":          "#0ADCD9",
+		"pink":          "#FF60FF",
+	},
+	"light": {
+		"primary":       "#5A3FE0",
+		"secondary":     "#B62DB6",
+		"tertiary":      "#0A7F6C",
+		"accent":        "#8A6A00",
+		"bg_base":       "#FAF9FB",
+		"bg_lighter":    "#F0EEF4",
+		"bg_subtle":     "#E0DDE8",
+		"bg_overlay":    "#CFCBD9",
+		"fg_base":       "#2A2833",
+		"fg_muted":      "#66647A",
+		"fg_half_muted": "#45434F",
+		"fg_subtle":     "#8E8C99",
+		"fg_bright":     "#111016",
+		"success":       "#0A8552",
+		"error":         "#C8213F",
+		"warning":       "#935F00",
+		"info":          "#0066BF",
+		"blue":          "#0066BF",
+		"green":         "#1E8A3C",
+		"green_dark":    "#0F6E4F",
+		"red":           "#D0304F",
+		"red_dark":      "#A81A36",
+		"yellow":        "#A37C00",
+		"orange":        "#C2580A",
+		"purple":        "#6B4FD8",
+		"cyan":          "#00838F",
+		"pink":          "#C23BAE",
+	},
+	"high-contrast": {
+		"primary":       "#FFFF00",
+		"secondary":     "#FF00FF",
+		"tertiary":      "#00FFFF",
+		"accent":        "#FFFF00",
+		"bg_base":       "#000000",
+		"bg_lighter":    "#000000",
+		"bg_subtle":     "#005FAF",
+		"bg_overlay":    "#3A3A3A",
+		"fg_base":       "#FFFFFF",
+		"fg_muted":      "#D0D0D0",
+		"fg_half_muted": "#E4E4E4",
+		"fg_subtle":     "#B0B0B0",
+		"fg_bright":     "#FFFFFF",
+		"success":       "#00FF00",
+		"error":         "#FF3030",
+		"warning":       "#FFFF00",
+		"info":          "#00C8FF",
+		"blue":          "#5FAFFF",
+		"green":         "#00FF00",
+		"green_dark":    "#00E000",
+		"red":           "#FF3030",
+		"red_dark":      "#FF0000",
+		"yellow":        "#FFFF00",
+		"orange":        "#FFA500",
+		"purple":        "#D787FF",
+		"cyan":          "#00FFFF",
+		"pink":          "#FF87FF",
+	},
+	"ansi16": {
+		"primary":       "5",
+		"secondary":     "13",
+		"tertiary":      "6",
+		"accent":        "3",
+		"bg_base":       "0",
+		"bg_lighter":    "",
+		"bg_subtle":     "8",
+		"bg_overlay":    "8",
+		"fg_base":       "",
+		"fg_muted":      "8",
+		"fg_half_muted": "",
+		"fg_subtle":     "8",
+		"fg_bright":     "15",
+		"success":       "2",
+		"error":         "1",
+		"warning":       "3",
+		"info":          "4",
+		"blue":          "4",
+		"green":         "2",
+		"green_dark":    "2",
+		"red":           "9",
+		"red_dark":      "1",
+		"yellow":        "3",
+		"orange":        "11",
+		"purple":        "5",
+		"cyan":          "6",
+		"pink":          "13",
+	},
+}
+
+func BuiltinThemeNames() []string {
+	return slices.Sorted(maps.Keys(builtinThemes))
+}
+
+func ThemeNames(userThemes map[string]map[string]string) []string {
+	names := BuiltinThemeNames()
+
+	for _, name := range slices.Sorted(maps.Keys(userThemes)) {
+		if _, builtin := builtinThemes[name]; !builtin {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func DetectTheme() string {
+	if lipgloss.HasDarkBackground() {
+		return "dark"
+	}
+
+	return "light"
+}
+
+func Monochrome() bool {
+	return termenv.EnvNoColor()
+}
+
+func ThemeColors(name string, userThemes map[string]map[string]string) (map[string]string, error) {
+	return resolveTheme(name, userThemes, nil)
+}
+
+func resolveTheme(name string, userThemes map[string]map[string]string, visited []string) (map[string]string, error) {
+	if name == AutoTheme {
+		name = DetectTheme()
+	}
+
+	if slices.Contains(visited, name) {
+		return nil, fmt.Errorf("theme %s is based on itself through %s", name, strings.Join(append(visited, name), " → "))
+	}
+
+	if builtinColors, isBuiltin := builtinThemes[name]; isBuiltin {
+		return maps.Clone(builtinColors), nil
+	}
+
+	userColors, isUserTheme := userThemes[name]
+
+	if !isUserTheme {
+		return nil, fmt.Errorf("unknown theme %q; known themes are %s, %s", name, AutoTheme,
+			strings.Join(ThemeNames(userThemes), ", "))
+	}
+
+	baseName := userColors["base"]
+
+	if baseName == "" {
+		baseName = "dark"
+	}
+
+	if _, isBuiltin := builtinThemes[baseName]; !isBuiltin && baseName != AutoTheme && userThemes[baseName] == nil {
+		return nil, fmt.Errorf("theme %s is based on unknown theme %q", name, baseName)
+	}
+
+	palette, baseError := resolveTheme(baseName, userThemes, append(visited, name))
+
+	if baseError != nil {
+		return nil, baseError
+	}
+
+	for _, colorName := range slices.Sorted(maps.Keys(userColors)) {
+		value := userColors[colorName]
+
+		if colorName == "base" {
+			continue
+		}
+
+		if _, exists := palette[colorName]; !exists {
+			return nil, fmt.Errorf("theme %s: %q is not a palette colour; known colours are %s", name, colorName,
+				strings.Join(ColorNames(), ", "))
+		}
+
+		if validateError := ValidateColor(value); validateError != nil {
+			return nil, fmt.Errorf("theme %s: colour %s: %w", name, colorName, validateError)
+		}
+
+		palette[colorName] = value
+	}
+
+	return palette, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/Fuwn/faustus/internal/app"
 	"github.com/Fuwn/faustus/internal/claude"
@@ -9,6 +10,41 @@ import (
 )
 
 func main() {
+	flags := registerConfigFlags(flag.CommandLine)
+
+	flag.Parse()
+
+	loadedConfig, configError := flags.resolve(flag.CommandLine)
+
+	if configError != nil {
+		reportConfigError(configError)
+		os.Exit(2)
+	}
+
+	if applyError := applyConfig(&loadedConfig); applyError != nil {
+		reportConfigError(applyError)
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "config" {
+		os.Exit(runConfig(*flags.configPath, &loadedConfig, flag.Args()[1:]))
+	}
+
+	recoveredCount, recoverError := claude.RecoverTransactions()
+
+	if recoverError != nil {
+		fmt.Fprintf(os.Stderr, "Error recovering interrupted operations: %v\n", recoverError)
+	}
+
+	switch flag.Arg(0) {
+	case "doctor":
+		os.Exit(runDoctor(flag.Args()[1:], loadedConfig.Interface.DryRun))
+	case "reindex":
+		os.Exit(runReindex(flag.Args()[1:], loadedConfig.Interface.DryRun))
+	case "title":
+		os.Exit(runTitle(flag.Args()[1:], loadedConfig.Interface.DryRun))
+	}
+
 	sessions, err := claude.LoadAllSessions()
 
 	if err != nil {
@@ -16,7 +52,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	m := app.NewModel(sessions)
+	m := app.NewModel(sessions, &loadedConfig)
+
+	if recoveredCount > 0 {
+		m.Notify(fmt.Sprintf("Recovered %d interrupted operations", recoveredCount))
+	}
+
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {